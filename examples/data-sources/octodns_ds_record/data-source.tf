@@ -0,0 +1,7 @@
+data "octodns_ds_record" "sub" {
+  zone = "unit.tests"
+  name = "sub"
+}
+output "ds_record" {
+  value = data.octodns_ds_record.sub
+}
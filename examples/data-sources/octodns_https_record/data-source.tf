@@ -0,0 +1,7 @@
+data "octodns_https_record" "root" {
+  zone = "unit.tests"
+  name = "@"
+}
+output "https_record" {
+  value = data.octodns_https_record.root
+}
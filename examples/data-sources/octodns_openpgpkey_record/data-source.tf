@@ -0,0 +1,7 @@
+data "octodns_openpgpkey_record" "hugh" {
+  zone = "unit.tests"
+  name = "c93f1e400f26708f98cb19d936620da35eec8f72e57f9eec01c1afd6._openpgpkey"
+}
+output "openpgpkey_record" {
+  value = data.octodns_openpgpkey_record.hugh
+}
@@ -0,0 +1,7 @@
+data "octodns_svcb_record" "dns" {
+  zone = "unit.tests"
+  name = "_dns"
+}
+output "svcb_record" {
+  value = data.octodns_svcb_record.dns
+}
@@ -0,0 +1,7 @@
+data "octodns_tlsa_record" "mail" {
+  zone = "unit.tests"
+  name = "_25._tcp.mail"
+}
+output "tlsa_record" {
+  value = data.octodns_tlsa_record.mail
+}
@@ -0,0 +1,11 @@
+data "octodns_zone" "unit" {
+  zone = "unit.tests"
+}
+
+output "a_records" {
+  value = {
+    for s in data.octodns_zone.unit.subdomains : s.name => flatten([
+      for t in s.type : t.values if t.type == "A"
+    ])
+  }
+}
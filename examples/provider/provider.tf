@@ -24,3 +24,72 @@ provider "octodns" {
   }
 
 }
+
+
+provider "octodns" {
+  github_base_url = "https://github.example.com/"
+  github_org      = "example_org"
+  github_repo     = "dns_repo"
+
+  scope {
+    path = "zones"
+  }
+
+}
+
+
+provider "octodns" {
+  github_org  = "example_org"
+  github_repo = "dns_repo"
+
+  github_app {
+    app_id           = 123456
+    installation_id  = 7654321
+    private_key_file = "/path/to/app.private-key.pem"
+  }
+
+  scope {
+    path = "zones"
+  }
+
+}
+
+
+provider "octodns" {
+  git_provider        = "gitlab"
+  gitlab_access_token = "glpat-xxxxxxxxxxxxx"
+  gitlab_project      = "example_group/dns_repo"
+
+  scope {
+    path = "zones"
+  }
+
+}
+
+
+provider "octodns" {
+  git_provider = "local"
+  local_path   = "/path/to/dns_repo"
+  local_commit = true
+
+  scope {
+    path = "zones"
+  }
+
+}
+
+
+provider "octodns" {
+  github_access_token = "ghp_xxxxxxxxxxxxx"
+  github_org          = "example_org"
+  github_repo         = "dns_repo"
+
+  change_mode            = "pull_request"
+  pull_request_labels    = ["dns"]
+  pull_request_reviewers = ["octocat"]
+
+  scope {
+    path = "zones"
+  }
+
+}
@@ -6,3 +6,28 @@ resource "octodns_a_record" "root" {
 }
 
 
+
+resource "octodns_a_record" "www" {
+  zone   = "example.com"
+  name   = "www"
+  values = ["1.2.3.4"]
+
+  dynamic = {
+    pools = {
+      us = {
+        fallback = "eu"
+        values = [
+          { value = "5.5.5.5", weight = 2 },
+          { value = "6.6.6.6" },
+        ]
+      }
+      eu = {
+        values = [{ value = "7.7.7.7" }]
+      }
+    }
+    rules = [
+      { pool = "us", geos = ["NA-US", "NA-CA"] },
+      { pool = "eu" },
+    ]
+  }
+}
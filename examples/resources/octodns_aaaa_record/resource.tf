@@ -5,3 +5,15 @@ resource "octodns_aaaa_record" "aaaa" {
 }
 
 
+
+resource "octodns_aaaa_record" "geo" {
+  zone   = "example.com"
+  name   = "geo"
+  values = ["2001:db8::1"]
+
+  geo = {
+    "EU"       = ["2001:db8::2"]
+    "NA-US"    = ["2001:db8::3"]
+    "NA-US-CA" = ["2001:db8::4", "2001:db8::5"]
+  }
+}
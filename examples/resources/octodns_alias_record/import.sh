@@ -0,0 +1 @@
+terraform import octodns_alias_record.example "<scope> <zone> <name>"
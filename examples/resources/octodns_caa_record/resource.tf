@@ -1,7 +1,9 @@
 locals {
 
   caa_raw = [
-    { flags = 30, tag = "issue", value = "ca.unit.tests" },
+    { flags = 0, tag = "issue", value = "letsencrypt.org; validationmethods=dns-01" },
+    { flags = 0, tag = "issuewild", value = ";" },
+    { flags = 0, tag = "iodef", value = "mailto:security@example.com" },
   ]
 
   caa_values = [for v in local.caa_raw : "${v.flags} ${v.tag} ${v.value}"]
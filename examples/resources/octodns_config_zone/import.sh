@@ -0,0 +1 @@
+terraform import octodns_config_zone.example "<zone>"
@@ -0,0 +1,10 @@
+resource "octodns_zone" "example" {
+  zone        = "example.com"
+  nameservers = ["ns1.example.net.", "ns2.example.net."]
+}
+
+resource "octodns_config_zone" "example" {
+  zone    = octodns_zone.example.zone
+  sources = ["config"]
+  targets = ["route53"]
+}
@@ -0,0 +1 @@
+terraform import octodns_ds_record.example "<scope> <zone> <name>"
@@ -0,0 +1,9 @@
+resource "octodns_ds_record" "sub" {
+  zone = "example.com"
+  name = "sub"
+  ttl  = 3600
+
+  structured_values = [
+    { key_tag = 60485, algorithm = 13, digest_type = 2, digest = "385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1" },
+  ]
+}
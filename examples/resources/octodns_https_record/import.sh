@@ -0,0 +1 @@
+terraform import octodns_https_record.example "<scope> <zone> <name>"
@@ -0,0 +1,9 @@
+resource "octodns_https_record" "root" {
+  zone = "example.com"
+  name = "@"
+  ttl  = 300
+  values = [
+    "1 . alpn=h2,h3",
+    "2 cdn.example.com. alpn=h2 ipv4hint=192.0.2.1,192.0.2.2",
+  ]
+}
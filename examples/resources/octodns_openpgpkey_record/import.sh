@@ -0,0 +1 @@
+terraform import octodns_openpgpkey_record.example "<scope> <zone> <name>"
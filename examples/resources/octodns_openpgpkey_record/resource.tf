@@ -0,0 +1,6 @@
+resource "octodns_openpgpkey_record" "hugh" {
+  zone   = "example.com"
+  name   = "c93f1e400f26708f98cb19d936620da35eec8f72e57f9eec01c1afd6._openpgpkey"
+  ttl    = 3600
+  values = [filebase64("hugh.pgp")]
+}
@@ -0,0 +1 @@
+terraform import octodns_record.example "<scope> <zone> <name> <type>"
@@ -0,0 +1,20 @@
+variable "records" {
+  type = map(object({
+    name   = string
+    type   = string
+    values = list(string)
+  }))
+  default = {
+    www  = { name = "www", type = "CNAME", values = ["example.com."] }
+    mail = { name = "@", type = "MX", values = ["10 mx.example.com."] }
+  }
+}
+
+resource "octodns_record" "records" {
+  for_each = var.records
+
+  zone   = "example.com"
+  name   = each.value.name
+  type   = each.value.type
+  values = each.value.values
+}
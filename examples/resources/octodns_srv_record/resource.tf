@@ -30,4 +30,10 @@ resource "octodns_srv_record" "root" {
   values   = each.value
 }
 
-
+resource "octodns_srv_record" "https" {
+  zone = "example.com"
+  name = "_https._tcp"
+  structured_values = [
+    { priority = 10, weight = 5, port = 443, target = "www.example.com." },
+  ]
+}
@@ -0,0 +1 @@
+terraform import octodns_svcb_record.example "<scope> <zone> <name>"
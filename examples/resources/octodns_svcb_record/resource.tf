@@ -0,0 +1,6 @@
+resource "octodns_svcb_record" "dns" {
+  zone   = "example.com"
+  name   = "_dns"
+  ttl    = 300
+  values = ["1 dns.example.com. alpn=dot port=853"]
+}
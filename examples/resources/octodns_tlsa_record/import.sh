@@ -0,0 +1 @@
+terraform import octodns_tlsa_record.example "<scope> <zone> <name>"
@@ -0,0 +1,9 @@
+resource "octodns_tlsa_record" "mail" {
+  zone = "example.com"
+  name = "_25._tcp.mail"
+  ttl  = 3600
+
+  structured_values = [
+    { certificate_usage = 3, selector = 1, matching_type = 1, certificate_association_data = "385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1" },
+  ]
+}
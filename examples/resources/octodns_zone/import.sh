@@ -0,0 +1 @@
+terraform import octodns_zone.example "<scope> <zone>"
@@ -0,0 +1,4 @@
+resource "octodns_zone" "example" {
+  zone        = "example.com"
+  nameservers = ["ns1.example.net.", "ns2.example.net."]
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"errors"
+	"maps"
+	"slices"
+)
+
+// Change is a mutation made to a zone file. Changes are queued next to the
+// dirty zone so they can be replayed on a freshly fetched copy of the file
+// when a commit is rejected because the file changed upstream in the meantime.
+type Change interface {
+	apply(z *Zone) error
+}
+
+// RecordChange describes a single record mutation made to a zone.
+type RecordChange struct {
+	Subdomain string
+	Type      string
+	// Record holds a snapshot of the record after the change, nil for deletes.
+	Record *Record
+}
+
+// NewRecordUpsert returns a change that creates or overwrites the record with
+// the current values of record.
+func NewRecordUpsert(subdomain string, record *Record) RecordChange {
+	snapshot := &Record{}
+	snapshot.assign(record)
+	snapshot.Name = record.Name
+	snapshot.Type = record.Type
+
+	return RecordChange{
+		Subdomain: subdomain,
+		Type:      record.Type,
+		Record:    snapshot,
+	}
+}
+
+// NewRecordDelete returns a change that removes the record type from the subdomain.
+func NewRecordDelete(subdomain, rtype string) RecordChange {
+	return RecordChange{
+		Subdomain: subdomain,
+		Type:      rtype,
+	}
+}
+
+func (c RecordChange) IsDelete() bool {
+	return c.Record == nil
+}
+
+func (c RecordChange) apply(z *Zone) error {
+	return z.ApplyChange(c)
+}
+
+// assign copies the modelled fields and the octodns config from another
+// record. Keys we don't model are left as they are, as those are never changed
+// by the provider.
+func (r *Record) assign(from *Record) {
+	r.Values = slices.Clone(from.Values)
+	r.TTL = from.TTL
+	r.Terraform = from.Terraform
+
+	r.Octodns = from.Octodns
+	r.Ignored = from.Ignored
+	r.Included = slices.Clone(from.Included)
+	r.Excluded = slices.Clone(from.Excluded)
+
+	var dynamic *Dynamic
+	if from.Dynamic != nil {
+		dynamic = from.Dynamic.clone()
+	}
+	r.SetDynamic(dynamic)
+	r.Geo = maps.Clone(from.Geo)
+}
+
+// ApplyChange replays a change on the zone. Upserts create the subdomain and
+// record type when missing, deletes of records that are already gone are
+// ignored.
+func (z *Zone) ApplyChange(change RecordChange) (err error) {
+
+	sub, err := z.FindSubdomain(change.Subdomain)
+	if errors.Is(err, ErrSubdomainNotFound) {
+		if change.IsDelete() {
+			return nil
+		}
+		sub, err = z.CreateSubdomain(change.Subdomain)
+	}
+	if err != nil {
+		return err
+	}
+
+	if change.IsDelete() {
+		if _, err = sub.GetType(change.Type); err != nil {
+			if errors.Is(err, ErrTypeNotFound) {
+				return nil
+			}
+			return err
+		}
+		if err = sub.DeleteType(change.Type); err != nil {
+			return err
+		}
+		if err = sub.FindAllType(); err != nil {
+			return err
+		}
+		if len(sub.Types) == 0 {
+			return z.DeleteSubdomain(sub.Name)
+		}
+		return nil
+	}
+
+	record, err := sub.GetType(change.Type)
+	if errors.Is(err, ErrTypeNotFound) {
+		record, err = sub.CreateType(change.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	record.assign(change.Record)
+
+	return sub.UpdateYaml()
+}
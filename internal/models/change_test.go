@@ -0,0 +1,129 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestZone_ApplyChange_Upsert(t *testing.T) {
+
+	xZone, err := zoneFromYaml(UNIT_FILE_DEFAULT)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	rt := createEmptyType("unit", TYPE_A)
+	if err = rt.AddValueFromString("127.0.0.1"); err != nil {
+		t.Fatalf("AddValueFromString error: %s", err)
+	}
+	if err = rt.AddValueFromString("127.0.0.2"); err != nil {
+		t.Fatalf("AddValueFromString error: %s", err)
+	}
+
+	change := NewRecordUpsert("unit", rt)
+
+	// Changing the source record afterwards must not alter the queued change
+	rt.ClearValues()
+
+	if err = xZone.ApplyChange(change); err != nil {
+		t.Fatalf("ApplyChange error: %s", err)
+	}
+
+	compareZoneOutputWithFile(t, &xZone, UNIT_FILE_UNIT_ADDED)
+
+	// Replaying the same change again should leave the zone untouched
+	if err = xZone.ApplyChange(change); err != nil {
+		t.Fatalf("ApplyChange error: %s", err)
+	}
+
+	compareZoneOutputWithFile(t, &xZone, UNIT_FILE_UNIT_ADDED)
+
+}
+
+func TestZone_ApplyChange_Update(t *testing.T) {
+
+	xZone, err := zoneFromYaml(UNIT_FILE_DEFAULT)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	rt := createEmptyType("www", TYPE_A)
+	if err = rt.AddValueFromString("4.4.4.4"); err != nil {
+		t.Fatalf("AddValueFromString error: %s", err)
+	}
+
+	if err = xZone.ApplyChange(NewRecordUpsert("www", rt)); err != nil {
+		t.Fatalf("ApplyChange error: %s", err)
+	}
+
+	sub, err := xZone.FindSubdomain("www")
+	if err != nil {
+		t.Fatalf("FindSubdomain error: %s", err)
+	}
+	got, err := sub.GetType(TYPE_A.String())
+	if err != nil {
+		t.Fatalf("GetType error: %s", err)
+	}
+
+	validateStringValues(t, got, []string{"4.4.4.4"})
+	if got.TTL != 300 {
+		t.Errorf("TTL = %d, want %d", got.TTL, 300)
+	}
+
+}
+
+func TestZone_ApplyChange_Delete(t *testing.T) {
+
+	xZone, err := zoneFromYaml(UNIT_FILE_DEFAULT)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	if err = xZone.ApplyChange(NewRecordDelete("loc", TYPE_LOC.String())); err != nil {
+		t.Fatalf("ApplyChange error: %s", err)
+	}
+
+	compareZoneOutputWithFile(t, &xZone, UNIT_FILE_LOC_DELETED)
+
+	// Deleting records that are already gone is not an error
+	if err = xZone.ApplyChange(NewRecordDelete("loc", TYPE_LOC.String())); err != nil {
+		t.Errorf("ApplyChange on deleted subdomain error: %s", err)
+	}
+	if err = xZone.ApplyChange(NewRecordDelete("www", TYPE_MX.String())); err != nil {
+		t.Errorf("ApplyChange on missing type error: %s", err)
+	}
+
+	if _, err = xZone.FindSubdomain("loc"); !errors.Is(err, ErrSubdomainNotFound) {
+		t.Errorf("FindSubdomain after delete = %v, want %v", err, ErrSubdomainNotFound)
+	}
+
+}
+
+func TestNewRecordUpsert_DynamicSnapshot(t *testing.T) {
+	rt := createEmptyType("unit", TYPE_A)
+	if err := rt.AddValueFromString("1.1.1.1"); err != nil {
+		t.Fatalf("AddValueFromString error: %s", err)
+	}
+	rt.SetDynamic(&Dynamic{
+		Pools: map[string]DynamicPool{"us": {Values: []DynamicPoolValue{{Value: "2.2.2.2"}}}},
+		Rules: []DynamicRule{{Geos: []string{"NA-US"}, Pool: "us"}},
+	})
+
+	change := NewRecordUpsert("unit", rt)
+
+	// Changing the source record afterwards must not alter the queued change
+	rt.Dynamic.Pools["us"].Values[0].Value = "3.3.3.3"
+	rt.Dynamic.Pools["eu"] = DynamicPool{Values: []DynamicPoolValue{{Value: "4.4.4.4"}}}
+	rt.Dynamic.Rules[0].Geos[0] = "EU"
+
+	dynamic := change.Record.Dynamic
+	if got := dynamic.Pools["us"].Values[0].Value; got != "2.2.2.2" {
+		t.Errorf("pool value = %s, want 2.2.2.2", got)
+	}
+	if _, ok := dynamic.Pools["eu"]; ok {
+		t.Errorf("pool added to the record shows up in the change")
+	}
+	if got := dynamic.Rules[0].Geos[0]; got != "NA-US" {
+		t.Errorf("rule geo = %s, want NA-US", got)
+	}
+}
@@ -2,15 +2,15 @@ package models
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/google/go-github/v55/github"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"golang.org/x/oauth2"
 )
 
 const (
@@ -22,78 +22,147 @@ const (
 type GitClient interface {
 	AddScope(name, path, branch, ext string) error
 	SetScope(name, path, branch, ext string) error
-	GetZone(zone, scope string) (*Zone, error)
+	GetZone(ctx context.Context, zone, scope string) (*Zone, error)
+	CreateZone(ctx context.Context, zone, scope string) (*Zone, error)
 	SetBranch(branch string) error
 	SetAuthor(name, email string) error
-	MarkZoneDirty(zone *Zone, comment string)
-	FlushIfLast() error
+	SetPullRequest(options *PullRequestOptions) error
+	SetCommitSigning(options *CommitSigningOptions) error
+	SetConfigFile(filepath string) error
+	GetConfig(ctx context.Context) (*Config, error)
+	MarkConfigDirty(ctx context.Context, config *Config, comment string, change ConfigZoneChange)
+	Lock()
+	LockRead()
+	Unlock()
+	MarkZoneDirty(ctx context.Context, zone *Zone, comment string, changes ...Change)
+	FlushIfLast(ctx context.Context) error
 }
 
-type GitHubClient struct {
-	*github.Client
-	Owner         string
-	Repo          string
-	Scopes        map[string]Scope
-	Zones         map[string]*Zone
-	Mutex         sync.RWMutex
-	Branch        string
-	AuthorName    string
-	AuthorEmail   string
-	RetryLimit    int
+// backend is implemented by every git provider. baseClient takes care of
+// scopes, the zone cache, batching and retries, and calls the backend to
+// actually read and write zone files. Calls to the git provider are aborted
+// when the context is done.
+type backend interface {
+	// fetchZone loads a zone file from the repository, bypassing the zone
+	// cache. Returns an ErrZoneNotFound error when the file doesn't exist.
+	fetchZone(ctx context.Context, zone, scope string) (*Zone, error)
+	// saveZones makes a single attempt to commit the zone files, which share
+	// a branch, in a single commit. Either all zones are committed or none.
+	// Created zones must not exist yet, deleted zones are removed. The sha
+	// of every written zone is set to that of its new version.
+	// Failures that may succeed on a later attempt are returned as a
+	// *RetryableError.
+	saveZones(ctx context.Context, zones []*Zone, comment string) error
+}
+
+// zoneRevalidator is implemented by the git providers that can cheaply check
+// whether a cached zone file changed in the repository.
+type zoneRevalidator interface {
+	// revalidateZone loads the zone file again when it changed since the
+	// cached zone was read, nil is returned when it is unchanged.
+	revalidateZone(ctx context.Context, cached *Zone) (*Zone, error)
+}
+
+// RetryableError is returned by a backend when a commit failed but may
+// succeed when it is tried again.
+type RetryableError struct {
+	Err error
+	// Delay is the time the git provider asked us to wait, leave zero to
+	// use the exponential backoff.
+	Delay time.Duration
+	// Conflict is set when the file changed upstream since it was fetched.
+	Conflict bool
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// baseClient holds the state and logic shared by all git providers.
+type baseClient struct {
+	backend     backend
+	Scopes      map[string]Scope
+	Zones       map[string]*Zone
+	Mutex       sync.RWMutex
+	Branch      string
+	AuthorName  string
+	AuthorEmail string
+	RetryLimit  int
+	RetryDelay  time.Duration
+	// MaxRetryDelay caps the backoff. A git provider asking to wait longer
+	// fails the commit, as every operation waits for the lock meanwhile.
+	MaxRetryDelay time.Duration
 	BatchWindow   time.Duration
 	dirtyZones    map[string]*Zone
 	dirtyComments map[string][]string
+	dirtyChanges  map[string][]Change
 	InFlight      atomic.Int64
 
-	// SaveZoneFn overrides the real GitHub API call when set. Tests use this
+	// PullRequest is set when changes are proposed using pull requests
+	// instead of committed to the branch directly.
+	PullRequest  *PullRequestOptions
+	pullRequests map[string]*PullRequest
+	// requester is the backend when it supports pull requests.
+	requester pullRequester
+
+	// CommitSigning is set when commits are signed.
+	CommitSigning *CommitSigningOptions
+
+	// configName is the name of the octoDNS config file in CONFIG_SCOPE.
+	configName string
+
+	// SaveZonesFn overrides the real git provider call when set. Tests use this
 	// to intercept commits without hitting the network. Leave nil in production.
-	SaveZoneFn func(zone *Zone, comment string) error
+	SaveZonesFn func(zones []*Zone, comment string) error
 }
 
-func NewGitHubClient(accessToken, owner, repo string, retryLimit int) (GitClient, error) {
-
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: accessToken},
-	)
-	tc := oauth2.NewClient(ctx, ts)
-
-	return &GitHubClient{
-		Client:        github.NewClient(tc),
-		Owner:         owner,
-		Repo:          repo,
-		Zones:         map[string]*Zone{},
-		Scopes:        map[string]Scope{},
-		Branch:        "main",
-		AuthorEmail:   "",
-		AuthorName:    "",
-		RetryLimit:    retryLimit,
-		BatchWindow:   100 * time.Millisecond,
-		dirtyZones:    map[string]*Zone{},
-		dirtyComments: map[string][]string{},
-	}, nil
-
+func (b *baseClient) init(backend backend, retryLimit int) {
+	b.backend = backend
+	b.Zones = map[string]*Zone{}
+	b.Scopes = map[string]Scope{}
+	b.Branch = "main"
+	b.RetryLimit = retryLimit
+	b.RetryDelay = time.Second
+	b.MaxRetryDelay = time.Minute
+	b.BatchWindow = 100 * time.Millisecond
+	b.dirtyZones = map[string]*Zone{}
+	b.dirtyComments = map[string][]string{}
+	b.dirtyChanges = map[string][]Change{}
 }
 
-func (g *GitHubClient) SetBranch(branch string) error {
-	g.Branch = branch
+func (b *baseClient) SetBranch(branch string) error {
+	b.Branch = branch
 	return nil
 }
 
-func (g *GitHubClient) SetAuthor(name, email string) error {
-	g.AuthorName = name
-	g.AuthorEmail = email
+func (b *baseClient) SetAuthor(name, email string) error {
+	b.AuthorName = name
+	b.AuthorEmail = email
 	return nil
 }
 
-func (g *GitHubClient) AddScope(name, path, branch, ext string) error {
-	if _, ok := g.Scopes[name]; ok {
+func (b *baseClient) AddScope(name, path, branch, ext string) error {
+	if name == CONFIG_SCOPE {
+		return fmt.Errorf("scope name `%s` is reserved for the octoDNS config file", name)
+	}
+	if _, ok := b.Scopes[name]; ok {
 		return fmt.Errorf("duplicate scope name found for name `%s`", name)
 	}
-	return g.SetScope(name, path, branch, ext)
+	return b.setScope(name, path, branch, ext)
+}
+
+func (b *baseClient) SetScope(name, path, branch, ext string) error {
+	if name == CONFIG_SCOPE {
+		return fmt.Errorf("scope name `%s` is reserved for the octoDNS config file", name)
+	}
+	return b.setScope(name, path, branch, ext)
 }
 
-func (g *GitHubClient) SetScope(name, path, branch, ext string) error {
+func (b *baseClient) setScope(name, path, branch, ext string) error {
 	if name == "" {
 		name = DEFAULT_SCOPE
 	}
@@ -103,78 +172,158 @@ func (g *GitHubClient) SetScope(name, path, branch, ext string) error {
 	if ext == "" {
 		ext = DEFAULT_EXTENSION
 	}
-	g.Scopes[name] = NewScope(name, path, branch, ext)
+	b.Scopes[name] = NewScope(name, path, branch, ext)
 	return nil
 }
 
-func (g *GitHubClient) GetScope(name string) (scope Scope, err error) {
+func (b *baseClient) GetScope(name string) (scope Scope, err error) {
 	var ok bool
 	if name == "" {
 		name = DEFAULT_SCOPE
 	}
-	if scope, ok = g.Scopes[name]; !ok {
+	if scope, ok = b.Scopes[name]; !ok {
 		err = fmt.Errorf("undefined scope `%s`", name)
 	}
 	return
 }
 
-func (g *GitHubClient) GetZone(zone, scope string) (*Zone, error) {
-	sc, err := g.GetScope(scope)
+// GetZone returns the zone from the zone cache, or loads it from the
+// repository. Must be called with the client locked, see Lock and LockRead.
+func (b *baseClient) GetZone(ctx context.Context, zone, scope string) (*Zone, error) {
+	sc, err := b.GetScope(scope)
 	if err != nil {
 		return nil, err
 	}
 
 	filepath := sc.CreateFilePath(zone)
 
-	if _, ok := g.Zones[filepath]; ok {
-		return g.Zones[filepath], nil
+	if z, ok := b.Zones[filepath]; ok {
+		if z, err = b.revalidateZone(ctx, filepath, z); err != nil {
+			return nil, err
+		}
+		if z.deleted {
+			return nil, fmt.Errorf("%w: %s", ErrZoneNotFound, filepath)
+		}
+		return z, nil
 	}
 
-	options := &github.RepositoryContentGetOptions{Ref: sc.GetBranch(g.Branch)}
-	ctx := context.Background()
-	fileContent, _, _, err := g.Repositories.GetContents(ctx, g.Owner, g.Repo, filepath, options)
+	z, err := b.backend.fetchZone(ctx, zone, scope)
 	if err != nil {
 		return nil, err
 	}
+	b.Zones[filepath] = z
+	return z, nil
+}
+
+// revalidateZone returns the cached zone, or the latest version when the zone
+// file changed in the repository since it was read. Zones with pending
+// changes are kept, and so are zones we created, as the git provider may
+// still serve the version before our commit for a while.
+func (b *baseClient) revalidateZone(ctx context.Context, filepath string, cached *Zone) (*Zone, error) {
+	revalidator, ok := b.backend.(zoneRevalidator)
+	if !ok || cached.etag == "" || cached.deleted {
+		return cached, nil
+	}
+	if _, dirty := b.dirtyZones[filepath]; dirty {
+		return cached, nil
+	}
+
+	fresh, err := revalidator.revalidateZone(ctx, cached)
+	switch {
+	case errors.Is(err, ErrZoneNotFound):
+		delete(b.Zones, filepath)
+		return nil, err
+	case err != nil:
+		return nil, err
+	case fresh == nil:
+		return cached, nil
+	case fresh.sha == cached.sha:
+		// our own commit, the cached zone is the same
+		cached.etag = fresh.etag
+		return cached, nil
+	}
+	tflog.Debug(ctx, "GetZone: zone changed in the repository", map[string]interface{}{"file": filepath})
+	b.Zones[filepath] = fresh
+	return fresh, nil
+}
 
-	contents, err := fileContent.GetContent()
+// CreateZone returns a new, empty zone. The zone file is created when the
+// zone is marked dirty and flushed. Fails with ErrZoneAlreadyExists when the
+// zone file exists.
+func (b *baseClient) CreateZone(ctx context.Context, zone, scope string) (*Zone, error) {
+	sc, err := b.GetScope(scope)
 	if err != nil {
 		return nil, err
 	}
 
-	z := Zone{}
-	z.name = zone
-	z.scope = scope
-	z.sha = fileContent.GetSHA()
+	filepath := sc.CreateFilePath(zone)
+	if z, ok := b.Zones[filepath]; ok {
+		_, dirty := b.dirtyZones[filepath]
+		switch {
+		case !z.deleted:
+			return nil, fmt.Errorf("%w: %s", ErrZoneAlreadyExists, filepath)
+		case !dirty:
+			// deleted by our last commit, which the git provider may not
+			// serve yet
+			return NewZone(zone, scope), nil
+		}
+	}
 
-	err = z.ReadYaml([]byte(contents))
-	if err != nil {
+	_, err = b.backend.fetchZone(ctx, zone, scope)
+	if err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrZoneAlreadyExists, filepath)
+	}
+	if !errors.Is(err, ErrZoneNotFound) {
 		return nil, err
 	}
-	g.Zones[filepath] = &z
-	return &z, nil
+	return NewZone(zone, scope), nil
+}
+
+// Lock registers a write operation and takes the client lock. The operation
+// is registered BEFORE the lock is taken so all queued goroutines are
+// counted, FlushIfLast owns the matching deregistration.
+func (b *baseClient) Lock() {
+	b.InFlight.Add(1)
+	b.Mutex.Lock()
+}
+
+// LockRead takes the client lock for an operation that only reads zones, so
+// it doesn't race the zone cache and pending changes of write operations.
+// Unlike Lock it doesn't register a write operation, release it with Unlock.
+func (b *baseClient) LockRead() {
+	b.Mutex.Lock()
 }
 
-// MarkZoneDirty queues a zone to be written. Must be called with Mutex held.
-func (g *GitHubClient) MarkZoneDirty(zone *Zone, comment string) {
-	tflog.Debug(context.Background(), "MarkZoneDirty", map[string]interface{}{"inFlight": g.InFlight.Load()})
-	sc, err := g.GetScope(zone.scope)
+func (b *baseClient) Unlock() {
+	b.Mutex.Unlock()
+}
+
+// MarkZoneDirty queues a zone to be written together with the changes that
+// were made to it, the changes are replayed when the commit conflicts.
+// The zone is cached so later operations see it before it is flushed, which
+// matters for new zones. Must be called with Mutex held.
+func (b *baseClient) MarkZoneDirty(ctx context.Context, zone *Zone, comment string, changes ...Change) {
+	tflog.Debug(ctx, "MarkZoneDirty", map[string]interface{}{"inFlight": b.InFlight.Load()})
+	sc, err := b.GetScope(zone.scope)
 	if err != nil {
 		return
 	}
 	filepath := sc.CreateFilePath(zone.name)
-	g.dirtyZones[filepath] = zone
-	g.dirtyComments[filepath] = append(g.dirtyComments[filepath], comment)
+	b.Zones[filepath] = zone
+	b.dirtyZones[filepath] = zone
+	b.dirtyComments[filepath] = append(b.dirtyComments[filepath], comment)
+	b.dirtyChanges[filepath] = append(b.dirtyChanges[filepath], changes...)
 }
 
 // FlushIfLast decrements InFlight and, if this was the last operation,
-// writes all dirty zones to GitHub in a single call per zone.
+// writes all dirty zones in a single commit per branch. When proposing changes
+// the commits go to the feature branch and the pull request is opened or
+// updated afterwards.
 //
 // Call pattern in each CRUD method — note NO separate defer for InFlight:
 //
-//	InFlight.Add(+1)          // BEFORE Lock — counts self as queued
-//	Mutex.Lock()
-//	defer Mutex.Unlock()
+//	Lock()                    // InFlight.Add(+1) BEFORE Mutex.Lock — counts self as queued
+//	defer Unlock()
 //	... do work ...
 //	MarkZoneDirty(...)
 //	FlushIfLast()             // owns the InFlight.Add(-1)
@@ -195,124 +344,239 @@ func (g *GitHubClient) MarkZoneDirty(zone *Zone, comment string) {
 //
 // The sleep is synchronous inside Create/Update/Delete, so the provider
 // process cannot exit during it — Terraform waits for these calls to
-// return before considering the apply complete.
+// return before considering the apply complete. It is cut short when ctx
+// is done, the dirty zones are then left unwritten and ctx's error is
+// returned.
 //
 // Must be called with Mutex held.
-func (g *GitHubClient) FlushIfLast() error {
-	remaining := g.InFlight.Add(-1)
-	tflog.Debug(context.Background(), "FlushIfLast", map[string]interface{}{"remaining": remaining, "dirty": len(g.dirtyZones)})
+func (b *baseClient) FlushIfLast(ctx context.Context) error {
+	remaining := b.InFlight.Add(-1)
+	tflog.Debug(ctx, "FlushIfLast", map[string]interface{}{"remaining": remaining, "dirty": len(b.dirtyZones)})
 	if remaining > 0 {
 		return nil
 	}
 	// InFlight just hit 0. Wait one grace window for any goroutines that
 	// Terraform is about to dispatch — they will call InFlight.Add(+1)
 	// before trying to Lock, so we'll see them after the sleep.
-	time.Sleep(g.BatchWindow)
-	if g.InFlight.Load() > 0 {
-		tflog.Debug(context.Background(), "FlushIfLast: new operations arrived during grace window, skipping flush")
+	if err := sleepContext(ctx, b.BatchWindow); err != nil {
+		return err
+	}
+	if b.InFlight.Load() > 0 {
+		tflog.Debug(ctx, "FlushIfLast: new operations arrived during grace window, skipping flush")
 		return nil
 	}
-	if len(g.dirtyZones) == 0 {
+	if len(b.dirtyZones) == 0 {
 		return nil
 	}
-	tflog.Debug(context.Background(), "FlushIfLast: flushing dirty zones", map[string]interface{}{"count": len(g.dirtyZones)})
-	for filepath, zone := range g.dirtyZones {
-		comments := g.dirtyComments[filepath]
-		var comment string
-		if len(comments) == 1 {
-			comment = comments[0]
-		} else {
-			var creates, updates, deletes int
-			for _, c := range comments {
-				switch {
-				case strings.Contains(c, ": create "):
-					creates++
-				case strings.Contains(c, ": update "):
-					updates++
-				case strings.Contains(c, ": delete "):
-					deletes++
-				}
-			}
-			parts := []string{}
-			if creates > 0 {
-				parts = append(parts, fmt.Sprintf("%d creates", creates))
-			}
-			if updates > 0 {
-				parts = append(parts, fmt.Sprintf("%d updates", updates))
-			}
-			if deletes > 0 {
-				parts = append(parts, fmt.Sprintf("%d deletes", deletes))
+	tflog.Debug(ctx, "FlushIfLast: flushing dirty zones", map[string]interface{}{"count": len(b.dirtyZones)})
+	// group the dirty zones by the branch they are committed to, and collect
+	// the comments per target branch for the pull request body
+	filepaths := make([]string, 0, len(b.dirtyZones))
+	for filepath := range b.dirtyZones {
+		filepaths = append(filepaths, filepath)
+	}
+	sort.Strings(filepaths)
+
+	branches := []string{}
+	commits := map[string][]string{}
+	proposed := map[string][]string{}
+	for _, filepath := range filepaths {
+		zone := b.dirtyZones[filepath]
+		if b.PullRequest != nil {
+			base, err := b.prepareBranch(ctx, zone)
+			if err != nil {
+				return err
 			}
-			comment = fmt.Sprintf("chore(%s/%s): %d changes (%s)", zone.scope, zone.name, len(comments), strings.Join(parts, ", "))
+			proposed[base] = append(proposed[base], b.dirtyComments[filepath]...)
+		}
+
+		sc, err := b.GetScope(zone.scope)
+		if err != nil {
+			return err
 		}
-		if err := g.SaveZone(zone, comment); err != nil {
+		branch, err := b.zoneBranch(ctx, sc)
+		if err != nil {
 			return err
 		}
-		delete(g.dirtyZones, filepath)
-		delete(g.dirtyComments, filepath)
+		if _, ok := commits[branch]; !ok {
+			branches = append(branches, branch)
+		}
+		commits[branch] = append(commits[branch], filepath)
+	}
+
+	for _, branch := range branches {
+		zones := []*Zone{}
+		comments := []string{}
+		for _, filepath := range commits[branch] {
+			zones = append(zones, b.dirtyZones[filepath])
+			comments = append(comments, b.dirtyComments[filepath]...)
+		}
+		if err := b.SaveZones(ctx, zones, b.commitMessage(zones, comments)); err != nil {
+			return err
+		}
+		for _, filepath := range commits[branch] {
+			delete(b.dirtyZones, filepath)
+			delete(b.dirtyComments, filepath)
+			delete(b.dirtyChanges, filepath)
+		}
+	}
+	if b.PullRequest != nil {
+		return b.proposeChanges(ctx, proposed)
 	}
 	return nil
 }
 
-func (g *GitHubClient) SaveZone(zone *Zone, comment string) error {
+// commitMessage returns the single comment as is, or a summary of all
+// actions when multiple changes are batched into one commit.
+func (b *baseClient) commitMessage(zones []*Zone, comments []string) string {
+	if len(comments) == 1 {
+		return comments[0]
+	}
+
+	var creates, updates, deletes int
+	for _, c := range comments {
+		switch {
+		case strings.Contains(c, ": create "):
+			creates++
+		case strings.Contains(c, ": update "):
+			updates++
+		case strings.Contains(c, ": delete "):
+			deletes++
+		}
+	}
+	parts := []string{}
+	if creates > 0 {
+		parts = append(parts, fmt.Sprintf("%d creates", creates))
+	}
+	if updates > 0 {
+		parts = append(parts, fmt.Sprintf("%d updates", updates))
+	}
+	if deletes > 0 {
+		parts = append(parts, fmt.Sprintf("%d deletes", deletes))
+	}
+	if len(zones) == 1 {
+		return fmt.Sprintf("chore(%s/%s): %d changes (%s)", zones[0].scope, zones[0].name, len(comments), strings.Join(parts, ", "))
+	}
+	return fmt.Sprintf("chore: %d changes in %d zones (%s)", len(comments), len(zones), strings.Join(parts, ", "))
+}
+
+// SaveZones commits the zones, which must share a branch, in a single commit.
+// The zones stay cached at their committed version, as reading them back
+// could return the version before the commit for a while.
+func (b *baseClient) SaveZones(ctx context.Context, zones []*Zone, comment string) error {
 	if comment == "" {
-		comment = fmt.Sprintf("chore(%s/%s): updating records", zone.scope, zone.name)
+		comment = "chore: updating records"
+		if len(zones) == 1 {
+			comment = fmt.Sprintf("chore(%s/%s): updating records", zones[0].scope, zones[0].name)
+		}
 	}
 
-	save := g.saveZoneViaAPI
-	if g.SaveZoneFn != nil {
-		save = g.SaveZoneFn
+	save := func(zones []*Zone, comment string) error {
+		return b.saveZonesWithRetry(ctx, zones, comment)
 	}
-	if err := save(zone, comment); err != nil {
+	if b.SaveZonesFn != nil {
+		save = b.SaveZonesFn
+	}
+	if err := save(zones, comment); err != nil {
 		return err
 	}
 
-	scope, err := g.GetScope(zone.scope)
-	if err != nil {
-		return err
+	for _, zone := range zones {
+		zone.created = false
 	}
-	delete(g.Zones, scope.CreateFilePath(zone.name))
 	return nil
 }
 
-func (g *GitHubClient) saveZoneViaAPI(zone *Zone, comment string) error {
-	content, err := zone.WriteYaml()
-	if err != nil {
-		return err
+// saveZonesWithRetry commits the zones using the backend. When the commit is
+// rejected because a file changed since it was fetched, the zones are
+// fetched again and the pending record changes are replayed on top of them.
+// Conflicts, rate limits and server errors are retried up to RetryLimit times
+// with an exponential backoff, unless the git provider tells us how long to wait.
+func (b *baseClient) saveZonesWithRetry(ctx context.Context, zones []*Zone, comment string) error {
+	filepaths := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		scope, err := b.GetScope(zone.scope)
+		if err != nil {
+			return err
+		}
+		filepaths = append(filepaths, scope.CreateFilePath(zone.name))
 	}
+	files := strings.Join(filepaths, ", ")
 
-	scope, err := g.GetScope(zone.scope)
-	if err != nil {
-		return err
-	}
+	for attempt := 0; ; attempt++ {
+		err := b.backend.saveZones(ctx, zones, comment)
+		if err == nil {
+			return nil
+		}
 
-	filepath := scope.CreateFilePath(zone.name)
-	sha := zone.sha
+		var retryErr *RetryableError
+		if !errors.As(err, &retryErr) {
+			return err
+		}
+		if attempt >= b.RetryLimit {
+			return fmt.Errorf("giving up on %s after %d retries: %w", files, attempt, err)
+		}
 
-	var author *github.CommitAuthor = nil
-	if g.AuthorName != "" || g.AuthorEmail != "" {
-		author = &github.CommitAuthor{}
-		if g.AuthorName != "" {
-			author.Name = github.String(g.AuthorName)
+		delay := retryErr.Delay
+		switch {
+		case delay > b.MaxRetryDelay:
+			return fmt.Errorf("rate limited until %s, not retrying %s: %w", time.Now().Add(delay).Format(time.RFC3339), files, err)
+		case delay <= 0:
+			delay = min(b.RetryDelay<<attempt, b.MaxRetryDelay)
 		}
-		if g.AuthorEmail != "" {
-			author.Email = github.String(g.AuthorEmail)
+
+		tflog.Debug(ctx, "saveZones: retrying", map[string]interface{}{"files": files, "attempt": attempt + 1, "delay": delay.String(), "error": err.Error()})
+		if err = sleepContext(ctx, delay); err != nil {
+			return err
 		}
+
+		if retryErr.Conflict {
+			for i, zone := range zones {
+				if err = b.rebaseZone(ctx, zone, b.dirtyChanges[filepaths[i]]); err != nil {
+					return fmt.Errorf("could not replay changes on %s after conflict: %w", filepaths[i], err)
+				}
+			}
+		}
+	}
+}
+
+// rebaseZone replaces the contents of zone with the latest version from the
+// repository and replays the given changes on top of it. A created zone must
+// still be missing and a deleted zone unchanged, as those checks can't be
+// replayed.
+func (b *baseClient) rebaseZone(ctx context.Context, zone *Zone, changes []Change) error {
+	fresh, err := b.backend.fetchZone(ctx, zone.name, zone.scope)
+	switch {
+	case zone.created && errors.Is(err, ErrZoneNotFound):
+		return nil
+	case zone.created && err == nil:
+		return ErrZoneAlreadyExists
+	case err != nil:
+		return err
+	case zone.deleted && fresh.sha != zone.sha:
+		return fmt.Errorf("zone changed since it was deleted")
+	case zone.deleted:
+		return nil
 	}
 
-	commitOption := &github.RepositoryContentFileOptions{
-		Branch:    github.String(scope.GetBranch(g.Branch)),
-		Message:   github.String(comment),
-		Committer: author,
-		Author:    author,
-		Content:   content,
-		SHA:       &sha,
+	for _, change := range changes {
+		if err = change.apply(fresh); err != nil {
+			return err
+		}
 	}
+	*zone = *fresh
+	return nil
+}
 
-	ctx := context.Background()
-	_, response, err := g.Repositories.UpdateFile(ctx, g.Owner, g.Repo, filepath, commitOption)
-	if response != nil && response.StatusCode == 409 {
-		return fmt.Errorf("409 error:`%v`", err)
+// sleepContext sleeps for d, or returns the error of ctx when it is done
+// before that.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
-	return err
 }
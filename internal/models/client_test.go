@@ -1,19 +1,31 @@
 package models
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v55/github"
 )
 
 type savedCommit struct {
-	zone    string
+	zones   []string
 	comment string
 }
 
 // newBatchingTestClient returns a GitHubClient wired up for batching tests:
-// a default scope is registered, SaveZoneFn records every commit into the
+// a default scope is registered, SaveZonesFn records every commit into the
 // returned slice under the shared mutex, and BatchWindow is short so tests
 // stay fast.
 func newBatchingTestClient(t *testing.T) (*GitHubClient, *[]savedCommit, *sync.Mutex) {
@@ -22,17 +34,17 @@ func newBatchingTestClient(t *testing.T) (*GitHubClient, *[]savedCommit, *sync.M
 	var commitsMu sync.Mutex
 	commits := []savedCommit{}
 
-	client := &GitHubClient{
-		Scopes:        map[string]Scope{},
-		Zones:         map[string]*Zone{},
-		dirtyZones:    map[string]*Zone{},
-		dirtyComments: map[string][]string{},
-		BatchWindow:   5 * time.Millisecond,
-	}
-	client.SaveZoneFn = func(z *Zone, c string) error {
+	client := &GitHubClient{}
+	client.init(client, 0)
+	client.BatchWindow = 5 * time.Millisecond
+	client.SaveZonesFn = func(zones []*Zone, c string) error {
 		commitsMu.Lock()
 		defer commitsMu.Unlock()
-		commits = append(commits, savedCommit{zone: z.name, comment: c})
+		names := []string{}
+		for _, z := range zones {
+			names = append(names, z.name)
+		}
+		commits = append(commits, savedCommit{zones: names, comment: c})
 		return nil
 	}
 
@@ -44,16 +56,15 @@ func newBatchingTestClient(t *testing.T) (*GitHubClient, *[]savedCommit, *sync.M
 }
 
 // runOperation simulates the caller-side pattern used by Create/Update/Delete
-// in record_resource.go: bump InFlight before taking the lock, mark dirty,
-// then FlushIfLast which owns the matching decrement.
+// in record_resource.go: Lock bumps InFlight before taking the lock, mark
+// dirty, then FlushIfLast which owns the matching decrement.
 func runOperation(client *GitHubClient, zoneName, comment string) error {
-	client.InFlight.Add(1)
-	client.Mutex.Lock()
-	defer client.Mutex.Unlock()
+	client.Lock()
+	defer client.Unlock()
 
 	zone := &Zone{name: zoneName, scope: "default"}
-	client.MarkZoneDirty(zone, comment)
-	return client.FlushIfLast()
+	client.MarkZoneDirty(context.Background(), zone, comment)
+	return client.FlushIfLast(context.Background())
 }
 
 func TestBatching_SingleOperation(t *testing.T) {
@@ -155,15 +166,15 @@ func TestBatching_MultipleZones(t *testing.T) {
 
 	mu.Lock()
 	defer mu.Unlock()
-	if len(*commits) != 2 {
-		t.Fatalf("expected 2 commits (one per zone), got %d", len(*commits))
+	if len(*commits) != 1 {
+		t.Fatalf("expected 1 commit for both zones, got %d", len(*commits))
 	}
-	seen := map[string]bool{}
-	for _, c := range *commits {
-		seen[c.zone] = true
+	if diff := cmp.Diff([]string{"zone-a.com", "zone-b.com"}, (*commits)[0].zones); diff != "" {
+		t.Errorf("unexpected zones in commit (-want +got):\n%s", diff)
 	}
-	if !seen["zone-a.com"] || !seen["zone-b.com"] {
-		t.Errorf("expected commits for both zones, got: %+v", *commits)
+	want := "chore: 4 changes in 2 zones (4 creates)"
+	if (*commits)[0].comment != want {
+		t.Errorf("expected summary %q, got %q", want, (*commits)[0].comment)
 	}
 }
 
@@ -175,15 +186,15 @@ func TestFlushIfLast_NotLastNoFlush(t *testing.T) {
 	// remains queued for the third goroutine (which this test doesn't run).
 	client.InFlight.Add(3)
 	client.Mutex.Lock()
-	client.MarkZoneDirty(&Zone{name: "example.com", scope: "default"}, "chore(default/example.com): create A record for x")
-	if err := client.FlushIfLast(); err != nil {
+	client.MarkZoneDirty(context.Background(), &Zone{name: "example.com", scope: "default"}, "chore(default/example.com): create A record for x")
+	if err := client.FlushIfLast(context.Background()); err != nil {
 		t.Fatalf("FlushIfLast failed: %s", err)
 	}
 	client.Mutex.Unlock()
 
 	client.Mutex.Lock()
-	client.MarkZoneDirty(&Zone{name: "example.com", scope: "default"}, "chore(default/example.com): create A record for y")
-	if err := client.FlushIfLast(); err != nil {
+	client.MarkZoneDirty(context.Background(), &Zone{name: "example.com", scope: "default"}, "chore(default/example.com): create A record for y")
+	if err := client.FlushIfLast(context.Background()); err != nil {
 		t.Fatalf("FlushIfLast failed: %s", err)
 	}
 	client.Mutex.Unlock()
@@ -204,7 +215,7 @@ func TestFlushIfLast_EmptyDirtyNoOp(t *testing.T) {
 	client.InFlight.Add(1)
 	client.Mutex.Lock()
 	// No MarkZoneDirty — dirty map stays empty.
-	if err := client.FlushIfLast(); err != nil {
+	if err := client.FlushIfLast(context.Background()); err != nil {
 		t.Fatalf("FlushIfLast failed: %s", err)
 	}
 	client.Mutex.Unlock()
@@ -215,3 +226,813 @@ func TestFlushIfLast_EmptyDirtyNoOp(t *testing.T) {
 		t.Fatalf("expected 0 commits when nothing dirty, got %d", len(*commits))
 	}
 }
+
+func TestFlushIfLast_Cancelled(t *testing.T) {
+	client, commits, mu := newBatchingTestClient(t)
+	client.BatchWindow = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.Lock()
+	client.MarkZoneDirty(ctx, &Zone{name: "example.com", scope: "default"}, "chore(default/example.com): create A record for x")
+	err := client.FlushIfLast(ctx)
+	client.Unlock()
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the batch window to be cancelled, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(*commits) != 0 {
+		t.Errorf("expected 0 commits after cancellation, got %d", len(*commits))
+	}
+}
+
+type fakeCommit struct {
+	files   map[string]string
+	parent  string
+	message string
+	author  string
+}
+
+type fakePull struct {
+	Number    int      `json:"number"`
+	State     string   `json:"state"`
+	Title     string   `json:"title"`
+	Body      string   `json:"body"`
+	Draft     bool     `json:"draft"`
+	Head      string   `json:"-"`
+	Base      string   `json:"-"`
+	Labels    []string `json:"-"`
+	Reviewers []string `json:"-"`
+}
+
+// fakeGitHub is a minimal stand-in for the GitHub contents, git data and pull
+// request APIs. Every branch points at a commit holding a snapshot of all
+// files, branches only move forward unless the update is forced.
+type fakeGitHub struct {
+	mu          sync.Mutex
+	commits     map[string]*fakeCommit
+	trees       map[string]map[string]string
+	refs        map[string]string // head commit per branch
+	pulls       []*fakePull
+	updates     int // ref update requests
+	notModified int // contents requests answered with 304 Not Modified
+	// stale serves the contents of a branch at the parent of its head, like
+	// a replica that didn't see the latest commit yet.
+	stale    bool
+	failures []int // status codes returned for the next ref updates
+	header   http.Header
+	// beforeUpdate is called with mu held when a ref update comes in, so
+	// tests can sneak in a concurrent commit.
+	beforeUpdate func()
+	// beforeRef is called likewise when the head of a branch is read.
+	beforeRef func()
+}
+
+func newFakeGitHub(t *testing.T, files map[string]string) (*fakeGitHub, *httptest.Server) {
+	t.Helper()
+
+	fake := &fakeGitHub{
+		commits: map[string]*fakeCommit{},
+		trees:   map[string]map[string]string{},
+		refs:    map[string]string{},
+	}
+	fake.commit("main", files, "initial")
+
+	server := httptest.NewServer(http.HandlerFunc(fake.ServeHTTP))
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+// commit adds a commit changing files on top of branch, creating the branch
+// when it doesn't exist.
+func (f *fakeGitHub) commit(branch string, files map[string]string, message string) string {
+	parent := f.refs[branch]
+	snapshot := map[string]string{}
+	if c, ok := f.commits[parent]; ok {
+		for k, v := range c.files {
+			snapshot[k] = v
+		}
+	}
+	for k, v := range files {
+		snapshot[k] = v
+	}
+
+	sha := fmt.Sprintf("commit-%d", len(f.commits)+1)
+	f.commits[sha] = &fakeCommit{files: snapshot, parent: parent, message: message}
+	f.refs[branch] = sha
+	return sha
+}
+
+// file returns the content of path at the head of branch.
+func (f *fakeGitHub) file(branch, path string) string {
+	return f.commits[f.refs[branch]].files[path]
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/")
+	w.Header().Set("Content-Type", "application/json")
+
+	notFound := func() {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	}
+
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "contents/"):
+		file := strings.TrimPrefix(path, "contents/")
+		ref := r.URL.Query().Get("ref")
+		if sha, ok := f.refs[ref]; ok {
+			ref = sha
+			if f.stale && f.commits[sha].parent != "" {
+				ref = f.commits[sha].parent
+			}
+		}
+		commit, ok := f.commits[ref]
+		if !ok {
+			notFound()
+			return
+		}
+		content, ok := commit.files[file]
+		if !ok {
+			notFound()
+			return
+		}
+		sha := gitBlobSHA([]byte(content))
+		etag := fmt.Sprintf("%q", sha)
+		if r.Header.Get("If-None-Match") == etag {
+			f.notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"type":     "file",
+			"encoding": "base64",
+			"path":     file,
+			"sha":      sha,
+			"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+		})
+
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "git/ref/heads/"):
+		if f.beforeRef != nil {
+			f.beforeRef()
+			f.beforeRef = nil
+		}
+		branch := strings.TrimPrefix(path, "git/ref/heads/")
+		sha, ok := f.refs[branch]
+		if !ok {
+			notFound()
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"ref":"refs/heads/%s","object":{"type":"commit","sha":"%s"}}`, branch, sha)
+
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "git/commits/"):
+		sha := strings.TrimPrefix(path, "git/commits/")
+		commit, ok := f.commits[sha]
+		if !ok {
+			notFound()
+			return
+		}
+		f.trees["tree-"+sha] = commit.files
+		_, _ = fmt.Fprintf(w, `{"sha":"%s","tree":{"sha":"tree-%s"}}`, sha, sha)
+
+	case r.Method == http.MethodPost && path == "git/trees":
+		var body struct {
+			BaseTree string `json:"base_tree"`
+			Tree     []struct {
+				Path    string  `json:"path"`
+				Content *string `json:"content"`
+			} `json:"tree"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		files := map[string]string{}
+		for k, v := range f.trees[body.BaseTree] {
+			files[k] = v
+		}
+		for _, entry := range body.Tree {
+			if entry.Content == nil {
+				delete(files, entry.Path)
+				continue
+			}
+			files[entry.Path] = *entry.Content
+		}
+		sha := fmt.Sprintf("tree-new-%d", len(f.trees)+1)
+		f.trees[sha] = files
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"sha":"%s"}`, sha)
+
+	case r.Method == http.MethodPost && path == "git/commits":
+		var body struct {
+			Message string   `json:"message"`
+			Tree    string   `json:"tree"`
+			Parents []string `json:"parents"`
+			Author  struct {
+				Name  string `json:"name"`
+				Email string `json:"email"`
+			} `json:"author"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		sha := fmt.Sprintf("commit-%d", len(f.commits)+1)
+		f.commits[sha] = &fakeCommit{
+			files:   f.trees[body.Tree],
+			parent:  body.Parents[0],
+			message: body.Message,
+			author:  body.Author.Name + " <" + body.Author.Email + ">",
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"sha":"%s"}`, sha)
+
+	case r.Method == http.MethodPost && path == "git/refs":
+		var body struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.refs[strings.TrimPrefix(body.Ref, "refs/heads/")] = body.SHA
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+
+	case r.Method == http.MethodPatch && strings.HasPrefix(path, "git/refs/heads/"):
+		f.updates++
+		branch := strings.TrimPrefix(path, "git/refs/heads/")
+		var body struct {
+			SHA   string `json:"sha"`
+			Force bool   `json:"force"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		if f.beforeUpdate != nil {
+			f.beforeUpdate()
+			f.beforeUpdate = nil
+		}
+		if len(f.failures) > 0 {
+			status := f.failures[0]
+			f.failures = f.failures[1:]
+			for k, v := range f.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"message":"failure","documentation_url":"https://docs.github.com/rest/overview/resources-in-the-rest-api#secondary-rate-limits"}`))
+			return
+		}
+		if _, ok := f.refs[branch]; !ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message":"Reference does not exist"}`))
+			return
+		}
+		if !body.Force && f.commits[body.SHA].parent != f.refs[branch] {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message":"Update is not a fast forward"}`))
+			return
+		}
+		f.refs[branch] = body.SHA
+		_, _ = w.Write([]byte(`{}`))
+
+	case r.Method == http.MethodGet && path == "pulls":
+		query := r.URL.Query()
+		found := []*fakePull{}
+		for _, pr := range f.pulls {
+			if pr.State == query.Get("state") && "owner:"+pr.Head == query.Get("head") && pr.Base == query.Get("base") {
+				found = append(found, pr)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(found)
+
+	case r.Method == http.MethodPost && path == "pulls":
+		var body struct {
+			Title string `json:"title"`
+			Head  string `json:"head"`
+			Base  string `json:"base"`
+			Body  string `json:"body"`
+			Draft bool   `json:"draft"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		pr := &fakePull{Number: len(f.pulls) + 1, State: "open", Title: body.Title, Body: body.Body, Draft: body.Draft, Head: body.Head, Base: body.Base}
+		f.pulls = append(f.pulls, pr)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(pr)
+
+	case strings.HasPrefix(path, "pulls/") || strings.HasPrefix(path, "issues/"):
+		parts := strings.Split(path, "/")
+		number, _ := strconv.Atoi(parts[1])
+		if number < 1 || number > len(f.pulls) {
+			notFound()
+			return
+		}
+		pr := f.pulls[number-1]
+
+		switch {
+		case r.Method == http.MethodPatch && len(parts) == 2:
+			var body struct {
+				Body string `json:"body"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			pr.Body = body.Body
+		case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "labels":
+			_ = json.NewDecoder(r.Body).Decode(&pr.Labels)
+			_, _ = w.Write([]byte(`[]`))
+			return
+		case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "requested_reviewers":
+			var body struct {
+				Reviewers     []string `json:"reviewers"`
+				TeamReviewers []string `json:"team_reviewers"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			pr.Reviewers = append(body.Reviewers, body.TeamReviewers...)
+		default:
+			notFound()
+			return
+		}
+		_ = json.NewEncoder(w).Encode(pr)
+
+	default:
+		notFound()
+	}
+}
+
+func newFakeGitHubClient(t *testing.T, server *httptest.Server) *GitHubClient {
+	t.Helper()
+
+	gh := github.NewClient(nil)
+	baseURL, _ := url.Parse(server.URL + "/")
+	gh.BaseURL = baseURL
+
+	client := &GitHubClient{
+		Client: gh,
+		Owner:  "owner",
+		Repo:   "repo",
+	}
+	client.init(client, 3)
+	client.RetryDelay = 0
+	client.BatchWindow = time.Millisecond
+	if err := client.AddScope("default", "zones", "main", "yaml"); err != nil {
+		t.Fatalf("AddScope failed: %s", err)
+	}
+	return client
+}
+
+// editRecord mimics a resource Create: change the zone in memory, mark it
+// dirty with the change and flush.
+func editRecord(t *testing.T, client GitClient, subdomain, value string) error {
+	t.Helper()
+
+	client.Lock()
+	defer client.Unlock()
+
+	zone, err := client.GetZone(context.Background(), "example.com", "default")
+	if err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+
+	rt := createEmptyType(subdomain, TYPE_A)
+	if err = rt.AddValueFromString(value); err != nil {
+		t.Fatalf("AddValueFromString failed: %s", err)
+	}
+	change := NewRecordUpsert(subdomain, rt)
+	if err = zone.ApplyChange(change); err != nil {
+		t.Fatalf("ApplyChange failed: %s", err)
+	}
+
+	client.MarkZoneDirty(context.Background(), zone, "chore(default/example.com): create A record for "+subdomain, change)
+	return client.FlushIfLast(context.Background())
+}
+
+func TestSaveZone_ConflictReplaysChanges(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitHubClient(t, server)
+
+	// Let someone else commit after the zone was read, before we commit
+	fake.beforeRef = func() {
+		fake.commit("main", map[string]string{"zones/example.com.yaml": "other:\n  type: A\n  value: 3.3.3.3\nwww:\n  type: A\n  value: 1.1.1.1\n"}, "other")
+	}
+
+	if err := editRecord(t, client, "new", "2.2.2.2"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.updates != 1 {
+		t.Errorf("expected 1 ref update, got %d", fake.updates)
+	}
+	want := "other:\n  type: A\n  value: 3.3.3.3\nwww:\n  type: A\n  value: 1.1.1.1\nnew:\n  - ttl: 300\n    type: A\n    value: 2.2.2.2\n"
+	if got := fake.file("main", "zones/example.com.yaml"); got != want {
+		t.Errorf("unexpected file content (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestGetZone_KeptAfterCommit(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitHubClient(t, server)
+
+	if err := editRecord(t, client, "new", "2.2.2.2"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	// The branch still serves the zone file from before our commit
+	fake.mu.Lock()
+	fake.stale = true
+	fake.mu.Unlock()
+
+	zone, err := client.GetZone(context.Background(), "example.com", "default")
+	if err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	types, _ := zone.RecordTypes()
+	if _, ok := types["new"]; !ok {
+		t.Errorf("expected the cached zone to hold our record, got %v", types)
+	}
+
+	// The next commit is made on top of ours without conflict
+	if err = editRecord(t, client, "other", "3.3.3.3"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.updates != 2 {
+		t.Errorf("expected 2 ref updates, got %d", fake.updates)
+	}
+}
+
+func TestGetZone_Revalidate(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitHubClient(t, server)
+
+	first, err := client.GetZone(context.Background(), "example.com", "default")
+	if err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	second, err := client.GetZone(context.Background(), "example.com", "default")
+	if err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	if first != second {
+		t.Errorf("expected the cached zone to be returned")
+	}
+
+	fake.mu.Lock()
+	if fake.notModified != 1 {
+		t.Errorf("expected 1 not modified response, got %d", fake.notModified)
+	}
+	fake.commit("main", map[string]string{"zones/example.com.yaml": "other:\n  type: A\n  value: 3.3.3.3\n"}, "other")
+	fake.mu.Unlock()
+
+	zone, err := client.GetZone(context.Background(), "example.com", "default")
+	if err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	if types, _ := zone.RecordTypes(); len(types) != 1 || types["other"] == nil {
+		t.Errorf("expected the zone to be read again, got %v", types)
+	}
+}
+
+func TestGetZone_DirtyNotRevalidated(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitHubClient(t, server)
+
+	client.LockRead()
+	defer client.Unlock()
+	zone, err := client.GetZone(context.Background(), "example.com", "default")
+	if err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	client.MarkZoneDirty(context.Background(), zone, "chore(default/example.com): create A record for new")
+
+	fake.mu.Lock()
+	fake.commit("main", map[string]string{"zones/example.com.yaml": "other:\n  type: A\n  value: 3.3.3.3\n"}, "other")
+	fake.mu.Unlock()
+
+	got, err := client.GetZone(context.Background(), "example.com", "default")
+	if err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	if got != zone {
+		t.Errorf("expected the dirty zone to be kept")
+	}
+}
+
+func TestSaveZone_NotFastForward(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitHubClient(t, server)
+
+	// Someone else commits between reading the branch and moving it
+	fake.beforeUpdate = func() {
+		fake.commit("main", map[string]string{"zones/example.com.yaml": "other:\n  type: A\n  value: 3.3.3.3\n"}, "other")
+	}
+
+	if err := editRecord(t, client, "new", "2.2.2.2"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.updates != 2 {
+		t.Errorf("expected 2 ref updates, got %d", fake.updates)
+	}
+	want := "other:\n  type: A\n  value: 3.3.3.3\nnew:\n  - ttl: 300\n    type: A\n    value: 2.2.2.2\n"
+	if got := fake.file("main", "zones/example.com.yaml"); got != want {
+		t.Errorf("unexpected file content (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+// editZones changes a record in each zone and flushes them together.
+func editZones(t *testing.T, client GitClient, subdomain, value string, zones ...string) error {
+	t.Helper()
+
+	client.Lock()
+	defer client.Unlock()
+
+	for _, name := range zones {
+		zone, err := client.GetZone(context.Background(), name, "default")
+		if err != nil {
+			t.Fatalf("GetZone failed: %s", err)
+		}
+
+		rt := createEmptyType(subdomain, TYPE_A)
+		if err = rt.AddValueFromString(value); err != nil {
+			t.Fatalf("AddValueFromString failed: %s", err)
+		}
+		change := NewRecordUpsert(subdomain, rt)
+		if err = zone.ApplyChange(change); err != nil {
+			t.Fatalf("ApplyChange failed: %s", err)
+		}
+		client.MarkZoneDirty(context.Background(), zone, fmt.Sprintf("chore(default/%s): create A record for %s", name, subdomain), change)
+	}
+	return client.FlushIfLast(context.Background())
+}
+
+func TestSaveZones_SingleCommit(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+		"zones/example.org.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitHubClient(t, server)
+	_ = client.SetAuthor("octodns", "octodns@example.com")
+
+	fake.mu.Lock()
+	initial := fake.refs["main"]
+	fake.mu.Unlock()
+
+	if err := editZones(t, client, "new", "2.2.2.2", "example.com", "example.org"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	head := fake.commits[fake.refs["main"]]
+	if head.parent != initial {
+		t.Errorf("expected a single commit on top of %s, got parent %s", initial, head.parent)
+	}
+	if head.message != "chore: 2 changes in 2 zones (2 creates)" {
+		t.Errorf("unexpected commit message: %q", head.message)
+	}
+	if head.author != "octodns <octodns@example.com>" {
+		t.Errorf("unexpected commit author: %q", head.author)
+	}
+	want := "www:\n  type: A\n  value: 1.1.1.1\nnew:\n  - ttl: 300\n    type: A\n    value: 2.2.2.2\n"
+	for _, file := range []string{"zones/example.com.yaml", "zones/example.org.yaml"} {
+		if got := head.files[file]; got != want {
+			t.Errorf("unexpected content of %s (-want +got):\n%s", file, cmp.Diff(want, got))
+		}
+	}
+}
+
+func TestSaveZones_NoneLandOnFailure(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+		"zones/example.org.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitHubClient(t, server)
+	fake.failures = []int{http.StatusUnprocessableEntity}
+
+	fake.mu.Lock()
+	initial := fake.refs["main"]
+	fake.mu.Unlock()
+
+	if err := editZones(t, client, "new", "2.2.2.2", "example.com", "example.org"); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.refs["main"] != initial {
+		t.Errorf("expected main to be untouched")
+	}
+}
+
+func TestSaveZone_RetryLimit(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitHubClient(t, server)
+	fake.failures = []int{http.StatusConflict, http.StatusConflict, http.StatusConflict, http.StatusConflict}
+
+	err := editRecord(t, client, "new", "2.2.2.2")
+	if err == nil {
+		t.Fatalf("expected an error after exhausting retries")
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.updates != client.RetryLimit+1 {
+		t.Errorf("expected %d ref updates, got %d", client.RetryLimit+1, fake.updates)
+	}
+}
+
+func TestSaveZone_RetryCancelled(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitHubClient(t, server)
+	client.RetryDelay = time.Hour
+	fake.failures = []int{http.StatusBadGateway}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	zone, err := client.GetZone(ctx, "example.com", "default")
+	if err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	err = client.SaveZones(ctx, []*Zone{zone}, "chore(default/example.com): update")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the retry to be cancelled, got %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.updates != 1 {
+		t.Errorf("expected 1 ref update, got %d", fake.updates)
+	}
+}
+
+func TestSaveZone_RetryOnSecondaryRateLimit(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitHubClient(t, server)
+	fake.failures = []int{http.StatusForbidden, http.StatusBadGateway}
+	fake.header = http.Header{"Retry-After": []string{"0"}}
+
+	if err := editRecord(t, client, "new", "2.2.2.2"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.updates != 3 {
+		t.Errorf("expected 3 ref updates, got %d", fake.updates)
+	}
+}
+
+func TestSaveZone_RateLimitedTooLong(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitHubClient(t, server)
+	fake.failures = []int{http.StatusForbidden}
+	fake.header = http.Header{"Retry-After": []string{"3600"}}
+
+	err := editRecord(t, client, "new", "2.2.2.2")
+	if err == nil || !strings.Contains(err.Error(), "rate limited until") {
+		t.Fatalf("expected a rate limited error, got %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.updates != 1 {
+		t.Errorf("expected 1 ref update, got %d", fake.updates)
+	}
+}
+
+func TestSaveZone_NoRetryOnClientError(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitHubClient(t, server)
+	fake.failures = []int{http.StatusUnprocessableEntity}
+
+	if err := editRecord(t, client, "new", "2.2.2.2"); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.updates != 1 {
+		t.Errorf("expected 1 ref update, got %d", fake.updates)
+	}
+}
+
+// createZone mimics the zone resource Create: create the zone, seed it with
+// a record and flush.
+func createZone(t *testing.T, client GitClient, name string) error {
+	t.Helper()
+
+	client.Lock()
+	defer client.Unlock()
+
+	zone, err := client.CreateZone(context.Background(), name, "default")
+	if err != nil {
+		_ = client.FlushIfLast(context.Background())
+		return err
+	}
+
+	rt := createEmptyType("", TYPE_NS)
+	if err = rt.AddValueFromString("ns1.example.net."); err != nil {
+		t.Fatalf("AddValueFromString failed: %s", err)
+	}
+	change := NewRecordUpsert("", rt)
+	if err = zone.ApplyChange(change); err != nil {
+		t.Fatalf("ApplyChange failed: %s", err)
+	}
+
+	client.MarkZoneDirty(context.Background(), zone, fmt.Sprintf("chore(default/%s): create zone", name), change)
+	return client.FlushIfLast(context.Background())
+}
+
+// deleteZone mimics the zone resource Delete.
+func deleteZone(t *testing.T, client GitClient, name string) error {
+	t.Helper()
+
+	client.Lock()
+	defer client.Unlock()
+
+	zone, err := client.GetZone(context.Background(), name, "default")
+	if err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	zone.Delete()
+
+	client.MarkZoneDirty(context.Background(), zone, fmt.Sprintf("chore(default/%s): delete zone", name))
+	return client.FlushIfLast(context.Background())
+}
+
+const createdZone = "'':\n  - ttl: 300\n    type: NS\n    value: ns1.example.net.\n"
+
+func TestCreateZone(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n"})
+	client := newFakeGitHubClient(t, server)
+
+	if err := createZone(t, client, "example.org"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	fake.mu.Lock()
+	got := fake.file("main", "zones/example.org.yaml")
+	fake.mu.Unlock()
+	if diff := cmp.Diff(createdZone, got); diff != "" {
+		t.Errorf("unexpected file content (-want +got):\n%s", diff)
+	}
+
+	if err := createZone(t, client, "example.com"); !errors.Is(err, ErrZoneAlreadyExists) {
+		t.Errorf("expected ErrZoneAlreadyExists, got %v", err)
+	}
+}
+
+func TestCreateZone_CreatedConcurrently(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{})
+	client := newFakeGitHubClient(t, server)
+
+	fake.beforeUpdate = func() {
+		fake.commit("main", map[string]string{"zones/example.org.yaml": "www:\n  type: A\n  value: 1.1.1.1\n"}, "other")
+	}
+	if err := createZone(t, client, "example.org"); !errors.Is(err, ErrZoneAlreadyExists) {
+		t.Errorf("expected ErrZoneAlreadyExists, got %v", err)
+	}
+}
+
+func TestDeleteZone(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+		"zones/example.org.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitHubClient(t, server)
+
+	if err := deleteZone(t, client, "example.org"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+	if _, err := client.GetZone(context.Background(), "example.org", "default"); !errors.Is(err, ErrZoneNotFound) {
+		t.Errorf("expected ErrZoneNotFound, got %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	files := fake.commits[fake.refs["main"]].files
+	if _, ok := files["zones/example.org.yaml"]; ok || len(files) != 1 {
+		t.Errorf("expected only example.org to be removed, got %v", files)
+	}
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CONFIG_SCOPE is the scope holding the octoDNS config file. The config file
+// is read and committed like a zone file, so it shares batching and retries.
+const CONFIG_SCOPE = "octodns-config"
+
+var ErrConfigZoneNotFound = errors.New("zone not found in config")
+
+// ConfigZone is an entry under `zones:` in the octoDNS config.
+type ConfigZone struct {
+	Name    string
+	Sources []string
+	Targets []string
+}
+
+// ConfigZoneChange adds, updates or removes an entry under `zones:`, and is
+// replayed when the commit of the config file conflicts.
+type ConfigZoneChange struct {
+	Zone   ConfigZone
+	Delete bool
+}
+
+func (c ConfigZoneChange) apply(z *Zone) error {
+	config := &Config{zone: z}
+	if c.Delete {
+		return config.DeleteZone(c.Zone.Name)
+	}
+	return config.SetZone(c.Zone)
+}
+
+// Config is the octoDNS config file. Only the zones are edited, everything
+// else, comments included, is written back as it was read.
+type Config struct {
+	zone *Zone
+}
+
+// configZoneKey returns the key of the zone in the config, octoDNS wants zone
+// names to end with a dot.
+func configZoneKey(name string) string {
+	return strings.TrimSuffix(name, ".") + "."
+}
+
+// mappingValue returns the value node of key in the mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// zones returns the mapping node under `zones:`, it is created when create is set.
+func (c *Config) zones(create bool) (*yaml.Node, error) {
+	if c.zone.doc.Kind != yaml.DocumentNode || c.zone.doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config is not a document with a mapping")
+	}
+	root := c.zone.doc.Content[0]
+
+	zones := mappingValue(root, "zones")
+	if zones == nil && create {
+		zones = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "zones"}, zones)
+	}
+	if zones != nil && zones.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("zones in config is not a mapping")
+	}
+	return zones, nil
+}
+
+// GetZone returns the config of the zone.
+func (c *Config) GetZone(name string) (ConfigZone, error) {
+	cz := ConfigZone{Name: name}
+
+	zones, err := c.zones(false)
+	if err != nil {
+		return cz, err
+	}
+	var entry *yaml.Node
+	if zones != nil {
+		entry = mappingValue(zones, configZoneKey(name))
+	}
+	if entry == nil {
+		return cz, fmt.Errorf("%w: %s", ErrConfigZoneNotFound, configZoneKey(name))
+	}
+
+	var decoded struct {
+		Sources []string `yaml:"sources"`
+		Targets []string `yaml:"targets"`
+	}
+	if err = entry.Decode(&decoded); err != nil {
+		return cz, err
+	}
+	cz.Sources = decoded.Sources
+	cz.Targets = decoded.Targets
+	return cz, nil
+}
+
+// SetZone adds the zone to the config, or replaces the sources and targets of
+// the zone when it is there already. Other keys of the entry, like
+// processors, are left alone.
+func (c *Config) SetZone(cz ConfigZone) error {
+	zones, err := c.zones(true)
+	if err != nil {
+		return err
+	}
+
+	key := configZoneKey(cz.Name)
+	entry := mappingValue(zones, key)
+	if entry == nil || entry.Kind != yaml.MappingNode {
+		if entry == nil {
+			entry = &yaml.Node{}
+			zones.Content = append(zones.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, entry)
+		}
+		*entry = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+
+	for _, list := range []struct {
+		key    string
+		values []string
+	}{{"sources", cz.Sources}, {"targets", cz.Targets}} {
+		node := &yaml.Node{}
+		if err = node.Encode(list.values); err != nil {
+			return err
+		}
+		if value := mappingValue(entry, list.key); value != nil {
+			// keep comments attached to the existing list
+			node.HeadComment, node.LineComment, node.FootComment = value.HeadComment, value.LineComment, value.FootComment
+			*value = *node
+			continue
+		}
+		entry.Content = append(entry.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: list.key}, node)
+	}
+	return nil
+}
+
+// DeleteZone removes the zone from the config.
+func (c *Config) DeleteZone(name string) error {
+	zones, err := c.zones(false)
+	if err != nil || zones == nil {
+		return err
+	}
+
+	key := configZoneKey(name)
+	for i := 0; i+1 < len(zones.Content); i += 2 {
+		if zones.Content[i].Value == key {
+			zones.Content = append(zones.Content[:i], zones.Content[i+2:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// SetConfigFile sets the path of the octoDNS config file in the repository,
+// eq: config/production.yaml.
+func (b *baseClient) SetConfigFile(filepath string) error {
+	filepath = strings.Trim(filepath, "/ ")
+	ext := path.Ext(filepath)
+	if ext == "" {
+		return fmt.Errorf("config file `%s` has no extension", filepath)
+	}
+
+	dir := path.Dir(filepath)
+	if dir == "." {
+		dir = ""
+	}
+	b.configName = strings.TrimSuffix(path.Base(filepath), ext)
+	return b.setScope(CONFIG_SCOPE, dir, "", ext)
+}
+
+// GetConfig returns the octoDNS config file. Must be called with the client
+// locked, like GetZone.
+func (b *baseClient) GetConfig(ctx context.Context) (*Config, error) {
+	if b.configName == "" {
+		return nil, fmt.Errorf("no octodns config file set")
+	}
+	zone, err := b.GetZone(ctx, b.configName, CONFIG_SCOPE)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{zone: zone}, nil
+}
+
+// MarkConfigDirty queues the config file to be written together with the
+// change that was made to it. Must be called with Mutex held.
+func (b *baseClient) MarkConfigDirty(ctx context.Context, config *Config, comment string, change ConfigZoneChange) {
+	b.MarkZoneDirty(ctx, config.zone, comment, change)
+}
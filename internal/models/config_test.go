@@ -0,0 +1,184 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const octodnsConfig = `---
+# managed by hand and terraform
+providers:
+  config:
+    class: octodns.provider.yaml.YamlProvider
+    directory: ./zones
+  route53:
+    class: octodns_route53.Route53Provider
+zones:
+  # the main zone
+  example.com.:
+    sources:
+      - config
+    targets:
+      - route53 # production
+    processors:
+      - only-these
+`
+
+func newConfigTestClient(t *testing.T) (*LocalClient, string) {
+	t.Helper()
+
+	client, dir := newLocalTestClient(t, false)
+	filename := filepath.Join(dir, "config", "production.yaml")
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, []byte(octodnsConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetConfigFile("config/production.yaml"); err != nil {
+		t.Fatalf("SetConfigFile failed: %s", err)
+	}
+	return client, filename
+}
+
+// editConfig mimics a config zone resource: change the config and flush.
+func editConfig(t *testing.T, client GitClient, change ConfigZoneChange) error {
+	t.Helper()
+
+	client.Lock()
+	defer client.Unlock()
+
+	config, err := client.GetConfig(context.Background())
+	if err != nil {
+		t.Fatalf("GetConfig failed: %s", err)
+	}
+	if err = change.apply(config.zone); err != nil {
+		t.Fatalf("apply failed: %s", err)
+	}
+	client.MarkConfigDirty(context.Background(), config, "chore(config): update zone "+change.Zone.Name, change)
+	return client.FlushIfLast(context.Background())
+}
+
+func TestConfig_GetZone(t *testing.T) {
+	client, _ := newConfigTestClient(t)
+
+	config, err := client.GetConfig(context.Background())
+	if err != nil {
+		t.Fatalf("GetConfig failed: %s", err)
+	}
+	got, err := config.GetZone("example.com")
+	if err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	want := ConfigZone{Name: "example.com", Sources: []string{"config"}, Targets: []string{"route53"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected config zone (-want +got):\n%s", diff)
+	}
+
+	if _, err = config.GetZone("example.org"); !errors.Is(err, ErrConfigZoneNotFound) {
+		t.Errorf("expected ErrConfigZoneNotFound, got %v", err)
+	}
+}
+
+func TestConfig_SetZone(t *testing.T) {
+	client, filename := newConfigTestClient(t)
+
+	if err := editConfig(t, client, ConfigZoneChange{Zone: ConfigZone{Name: "example.com", Sources: []string{"config"}, Targets: []string{"route53", "cloudflare"}}}); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+	if err := editConfig(t, client, ConfigZoneChange{Zone: ConfigZone{Name: "example.org.", Sources: []string{"config"}, Targets: []string{"route53"}}}); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	got, _ := os.ReadFile(filename)
+	want := `# managed by hand and terraform
+providers:
+  config:
+    class: octodns.provider.yaml.YamlProvider
+    directory: ./zones
+  route53:
+    class: octodns_route53.Route53Provider
+zones:
+  # the main zone
+  example.com.:
+    sources:
+      - config
+    targets:
+      - route53
+      - cloudflare
+    processors:
+      - only-these
+  example.org.:
+    sources:
+      - config
+    targets:
+      - route53
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("unexpected config (-want +got):\n%s", diff)
+	}
+}
+
+func TestConfig_DeleteZone(t *testing.T) {
+	client, filename := newConfigTestClient(t)
+
+	if err := editConfig(t, client, ConfigZoneChange{Zone: ConfigZone{Name: "example.com"}, Delete: true}); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	got, _ := os.ReadFile(filename)
+	want := `# managed by hand and terraform
+providers:
+  config:
+    class: octodns.provider.yaml.YamlProvider
+    directory: ./zones
+  route53:
+    class: octodns_route53.Route53Provider
+zones: {}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("unexpected config (-want +got):\n%s", diff)
+	}
+}
+
+func TestConfig_ConflictReplaysChanges(t *testing.T) {
+	client, filename := newConfigTestClient(t)
+
+	if _, err := client.GetConfig(context.Background()); err != nil {
+		t.Fatalf("GetConfig failed: %s", err)
+	}
+	other := "providers: {}\nzones:\n  example.net.:\n    sources: [config]\n    targets: [route53]\n"
+	if err := os.WriteFile(filename, []byte(other), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := editConfig(t, client, ConfigZoneChange{Zone: ConfigZone{Name: "example.org", Sources: []string{"config"}, Targets: []string{"route53"}}}); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	got, _ := os.ReadFile(filename)
+	want := "providers: {}\nzones:\n  example.net.:\n    sources: [config]\n    targets: [route53]\n  example.org.:\n    sources:\n      - config\n    targets:\n      - route53\n"
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("unexpected config (-want +got):\n%s", diff)
+	}
+}
+
+func TestConfig_ReservedScope(t *testing.T) {
+	client, _ := newConfigTestClient(t)
+
+	if err := client.AddScope(CONFIG_SCOPE, "zones", "", ""); err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("expected the config scope name to be reserved, got %v", err)
+	}
+	if err := client.SetScope(CONFIG_SCOPE, "zones", "", ""); err == nil {
+		t.Errorf("expected the config scope name to be reserved")
+	}
+	if scope, _ := client.GetScope(CONFIG_SCOPE); scope.CreateFilePath("production") != "config/production.yaml" {
+		t.Errorf("config scope should be left untouched, got %s", scope.CreateFilePath("production"))
+	}
+}
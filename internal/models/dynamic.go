@@ -0,0 +1,280 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DYNAMIC_TYPES are the record types octoDNS supports dynamic config for.
+var DYNAMIC_TYPES = []string{TYPE_A.String(), TYPE_AAAA.String(), TYPE_CNAME.String()}
+
+func SupportsDynamic(rtype string) bool {
+	return slices.Contains(DYNAMIC_TYPES, rtype)
+}
+
+// Dynamic is the `dynamic:` config of a record: pools of weighted values
+// and rules that pick a pool by geo or subnet. Pools that are down fall back
+// to another pool, and in the end to the values of the record itself.
+type Dynamic struct {
+	Pools map[string]DynamicPool
+	Rules []DynamicRule
+
+	// layout holds the keys of the dynamic config we don't model, poolsLayout
+	// the order of the pools as they were read.
+	layout      yamlMapping
+	poolsLayout yamlMapping
+}
+
+// DynamicPool, DynamicPoolValue and DynamicRule keep the keys we don't model
+// in their layout, like Dynamic does.
+type DynamicPool struct {
+	Fallback string             `yaml:",omitempty"`
+	Values   []DynamicPoolValue `yaml:"values"`
+
+	layout yamlMapping
+}
+
+type DynamicPoolValue struct {
+	Status string `yaml:",omitempty"`
+	Value  string `yaml:"value"`
+	Weight int    `yaml:",omitempty"`
+
+	layout yamlMapping
+}
+
+type DynamicRule struct {
+	Geos    []string `yaml:",omitempty"`
+	Pool    string   `yaml:"pool"`
+	Subnets []string `yaml:",omitempty"`
+
+	layout yamlMapping
+}
+
+type dynamicYaml struct {
+	Pools map[string]DynamicPool `yaml:"pools"`
+	Rules []DynamicRule          `yaml:"rules"`
+}
+
+var (
+	dynamicKeys          = []string{"pools", "rules"}
+	dynamicPoolKeys      = []string{"fallback", "values"}
+	dynamicPoolValueKeys = []string{"status", "value", "weight"}
+	dynamicRuleKeys      = []string{"geos", "pool", "subnets"}
+)
+
+func (d *Dynamic) UnmarshalYAML(value *yaml.Node) error {
+	raw := dynamicYaml{}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+
+	d.Pools = raw.Pools
+	d.Rules = raw.Rules
+	d.layout = readMapping(value, dynamicKeys)
+	d.poolsLayout = readOrder(mappingValue(value, "pools"))
+
+	return nil
+}
+
+func (d Dynamic) MarshalYAML() (interface{}, error) {
+	pools, err := d.poolsLayout.encode(d.Pools)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.layout.encode(struct {
+		Pools *yaml.Node    `yaml:"pools"`
+		Rules []DynamicRule `yaml:"rules"`
+	}{pools, d.Rules})
+}
+
+func (p *DynamicPool) UnmarshalYAML(value *yaml.Node) error {
+	type plain DynamicPool
+	if err := value.Decode((*plain)(p)); err != nil {
+		return err
+	}
+	p.layout = readMapping(value, dynamicPoolKeys)
+	return nil
+}
+
+func (p DynamicPool) MarshalYAML() (interface{}, error) {
+	type plain DynamicPool
+	return p.layout.encode(plain(p))
+}
+
+func (v *DynamicPoolValue) UnmarshalYAML(value *yaml.Node) error {
+	type plain DynamicPoolValue
+	if err := value.Decode((*plain)(v)); err != nil {
+		return err
+	}
+	v.layout = readMapping(value, dynamicPoolValueKeys)
+	return nil
+}
+
+func (v DynamicPoolValue) MarshalYAML() (interface{}, error) {
+	type plain DynamicPoolValue
+	return v.layout.encode(plain(v))
+}
+
+func (r *DynamicRule) UnmarshalYAML(value *yaml.Node) error {
+	type plain DynamicRule
+	if err := value.Decode((*plain)(r)); err != nil {
+		return err
+	}
+	r.layout = readMapping(value, dynamicRuleKeys)
+	return nil
+}
+
+func (r DynamicRule) MarshalYAML() (interface{}, error) {
+	type plain DynamicRule
+	return r.layout.encode(plain(r))
+}
+
+// SetDynamic replaces the dynamic config of the record, pools that were there
+// already keep their position in the file. Pools, values and rules that were
+// there already keep the keys we don't model: pools and values by name and
+// value, rules by position.
+func (r *Record) SetDynamic(d *Dynamic) {
+	if d != nil && r.Dynamic != nil {
+		d.layout = r.Dynamic.layout
+		d.poolsLayout = r.Dynamic.poolsLayout
+
+		for name, pool := range d.Pools {
+			old, ok := r.Dynamic.Pools[name]
+			if !ok {
+				continue
+			}
+			pool.layout = old.layout
+			for i := range pool.Values {
+				for _, oldValue := range old.Values {
+					if oldValue.Value == pool.Values[i].Value {
+						pool.Values[i].layout = oldValue.layout
+					}
+				}
+			}
+			d.Pools[name] = pool
+		}
+		for i := range d.Rules {
+			if i < len(r.Dynamic.Rules) {
+				d.Rules[i].layout = r.Dynamic.Rules[i].layout
+			}
+		}
+	}
+	r.Dynamic = d
+}
+
+// clone returns a deep copy of the dynamic config, so changes to either
+// don't show up in the other.
+func (d *Dynamic) clone() *Dynamic {
+	copied := &Dynamic{layout: d.layout, poolsLayout: d.poolsLayout}
+	if d.Pools != nil {
+		copied.Pools = make(map[string]DynamicPool, len(d.Pools))
+		for name, pool := range d.Pools {
+			pool.Values = slices.Clone(pool.Values)
+			copied.Pools[name] = pool
+		}
+	}
+	if d.Rules != nil {
+		copied.Rules = make([]DynamicRule, 0, len(d.Rules))
+		for _, rule := range d.Rules {
+			rule.Geos = slices.Clone(rule.Geos)
+			rule.Subnets = slices.Clone(rule.Subnets)
+			copied.Rules = append(copied.Rules, rule)
+		}
+	}
+	copied.layout.order = slices.Clone(d.layout.order)
+	copied.poolsLayout.order = slices.Clone(d.poolsLayout.order)
+	return copied
+}
+
+// Validate checks the dynamic config like octoDNS does: every pool value is a
+// valid value of the record type, rules and fallbacks point at defined pools,
+// fallbacks don't loop and every pool is used.
+func (d *Dynamic) Validate(rtype string) error {
+	if !SupportsDynamic(rtype) {
+		return fmt.Errorf("dynamic is only supported for %s records", strings.Join(DYNAMIC_TYPES, ", "))
+	}
+	if len(d.Pools) == 0 {
+		return fmt.Errorf("dynamic needs at least one pool")
+	}
+	if len(d.Rules) == 0 {
+		return fmt.Errorf("dynamic needs at least one rule")
+	}
+
+	names := make([]string, 0, len(d.Pools))
+	for name := range d.Pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pool := d.Pools[name]
+		if len(pool.Values) == 0 {
+			return fmt.Errorf("pool %s has no values", name)
+		}
+		for _, v := range pool.Values {
+			if err := ValidateValueString(rtype, v.Value); err != nil {
+				return fmt.Errorf("pool %s: invalid value %q: %w", name, v.Value, err)
+			}
+			if v.Weight < 0 || v.Weight > 100 {
+				return fmt.Errorf("pool %s: weight of %s should be between 1 and 100", name, v.Value)
+			}
+			if v.Status != "" && v.Status != "up" && v.Status != "down" && v.Status != "obey" {
+				return fmt.Errorf("pool %s: status of %s should be one of up, down or obey", name, v.Value)
+			}
+		}
+		if _, ok := d.Pools[pool.Fallback]; pool.Fallback != "" && !ok {
+			return fmt.Errorf("pool %s falls back to undefined pool %s", name, pool.Fallback)
+		}
+	}
+
+	used := map[string]bool{}
+	for _, name := range names {
+		chain := []string{name}
+		for next := d.Pools[name].Fallback; next != ""; next = d.Pools[next].Fallback {
+			chain = append(chain, next)
+			if slices.Contains(chain[:len(chain)-1], next) {
+				return fmt.Errorf("pool %s has a fallback loop: %s", name, strings.Join(chain, " -> "))
+			}
+			used[next] = true
+		}
+	}
+
+	for i, rule := range d.Rules {
+		if _, ok := d.Pools[rule.Pool]; !ok {
+			return fmt.Errorf("rule %d points at undefined pool %s", i+1, rule.Pool)
+		}
+		used[rule.Pool] = true
+
+		if len(rule.Geos) == 0 && len(rule.Subnets) == 0 && i != len(d.Rules)-1 {
+			return fmt.Errorf("rule %d has no geos or subnets, only the last rule may catch all", i+1)
+		}
+		for _, geo := range rule.Geos {
+			if err := ValidateGeoCode(geo); err != nil {
+				return fmt.Errorf("rule %d: %w", i+1, err)
+			}
+		}
+		for _, subnet := range rule.Subnets {
+			if _, _, err := net.ParseCIDR(subnet); err != nil {
+				return fmt.Errorf("rule %d: invalid subnet %q", i+1, subnet)
+			}
+		}
+	}
+
+	unused := []string{}
+	for _, name := range names {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		return fmt.Errorf("unused pools: %s", strings.Join(unused, ", "))
+	}
+
+	return nil
+}
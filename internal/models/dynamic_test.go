@@ -0,0 +1,256 @@
+package models
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const dynamicZone = `www:
+  dynamic:
+    pools:
+      us:
+        fallback: eu
+        values:
+          - value: 3.3.3.3
+            weight: 2
+          - status: down
+            value: 4.4.4.4
+      eu:
+        values:
+          - value: 2.2.2.2
+    rules:
+      - geos:
+          - NA-US
+        pool: us
+      - pool: eu
+  type: A
+  value: 1.1.1.1
+`
+
+func TestDynamic_ReadWrite(t *testing.T) {
+
+	xZone := Zone{}
+	if err := xZone.ReadYaml([]byte(dynamicZone)); err != nil {
+		t.Fatalf("ReadYaml error: %s", err)
+	}
+	sub, err := xZone.FindSubdomain("www")
+	if err != nil {
+		t.Fatalf("FindSubdomain throws an error: %s", err)
+	}
+	rt, err := sub.GetType(TYPE_A.String())
+	if err != nil {
+		t.Fatalf("GetType throws an error: %s", err)
+	}
+
+	want := &Dynamic{
+		Pools: map[string]DynamicPool{
+			"us": {Fallback: "eu", Values: []DynamicPoolValue{{Value: "3.3.3.3", Weight: 2}, {Status: "down", Value: "4.4.4.4"}}},
+			"eu": {Values: []DynamicPoolValue{{Value: "2.2.2.2"}}},
+		},
+		Rules: []DynamicRule{{Geos: []string{"NA-US"}, Pool: "us"}, {Pool: "eu"}},
+	}
+	if diff := cmp.Diff(want, rt.Dynamic, cmpopts.IgnoreUnexported(Dynamic{}, DynamicPool{}, DynamicPoolValue{}, DynamicRule{})); diff != "" {
+		t.Errorf("unexpected dynamic (-want +got):\n%s", diff)
+	}
+	if err = rt.Dynamic.Validate(TYPE_A.String()); err != nil {
+		t.Errorf("Validate throws an error: %s", err)
+	}
+
+	// Replacing the dynamic config keeps the order of the pools
+	dynamic := &Dynamic{
+		Pools: map[string]DynamicPool{
+			"us": want.Pools["us"],
+			"eu": want.Pools["eu"],
+			"ap": {Fallback: "eu", Values: []DynamicPoolValue{{Value: "5.5.5.5"}}},
+		},
+		Rules: []DynamicRule{{Geos: []string{"AS"}, Pool: "ap"}, {Geos: []string{"NA-US"}, Pool: "us"}, {Pool: "eu"}},
+	}
+	rt.SetDynamic(dynamic)
+	if err = sub.UpdateYaml(); err != nil {
+		t.Fatalf("UpdateYaml throws an error: %s", err)
+	}
+	out, err := xZone.WriteYaml()
+	if err != nil {
+		t.Fatalf("WriteYaml error: %s", err)
+	}
+
+	wantOut := `www:
+  dynamic:
+    pools:
+      ap:
+        fallback: eu
+        values:
+          - value: 5.5.5.5
+      us:
+        fallback: eu
+        values:
+          - value: 3.3.3.3
+            weight: 2
+          - status: down
+            value: 4.4.4.4
+      eu:
+        values:
+          - value: 2.2.2.2
+    rules:
+      - geos:
+          - AS
+        pool: ap
+      - geos:
+          - NA-US
+        pool: us
+      - pool: eu
+  type: A
+  value: 1.1.1.1
+`
+	if !bytes.Equal(out, []byte(wantOut)) {
+		t.Errorf("Output is not equal:\n%s", cmp.Diff(wantOut, string(out)))
+	}
+
+	// Removing the dynamic config removes the key
+	rt.SetDynamic(nil)
+	if err = sub.UpdateYaml(); err != nil {
+		t.Fatalf("UpdateYaml throws an error: %s", err)
+	}
+	if out, _ = xZone.WriteYaml(); strings.Contains(string(out), "dynamic") {
+		t.Errorf("expected dynamic to be removed, got:\n%s", out)
+	}
+}
+
+// dynamicExtrasZone has keys octoDNS providers read that we don't model, at
+// every level of the dynamic config.
+const dynamicExtrasZone = `www:
+  dynamic:
+    pools:
+      us:
+        fallback: eu
+        note: primary
+        values:
+          - value: 3.3.3.3
+            weight: 2
+            x-healthcheck: tcp
+      eu:
+        values:
+          - value: 2.2.2.2
+    rules:
+      - geos:
+          - NA-US
+        pool: us
+        x-comment: us only
+      - pool: eu
+  type: A
+  value: 1.1.1.1
+`
+
+func TestDynamic_RoundTripExtras(t *testing.T) {
+	xZone := Zone{}
+	if err := xZone.ReadYaml([]byte(dynamicExtrasZone)); err != nil {
+		t.Fatalf("ReadYaml error: %s", err)
+	}
+	sub, err := xZone.FindSubdomain("www")
+	if err != nil {
+		t.Fatalf("FindSubdomain throws an error: %s", err)
+	}
+	rt, err := sub.GetType(TYPE_A.String())
+	if err != nil {
+		t.Fatalf("GetType throws an error: %s", err)
+	}
+
+	if err = sub.UpdateYaml(); err != nil {
+		t.Fatalf("UpdateYaml throws an error: %s", err)
+	}
+	out, err := xZone.WriteYaml()
+	if err != nil {
+		t.Fatalf("WriteYaml error: %s", err)
+	}
+	if diff := cmp.Diff(dynamicExtrasZone, string(out)); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+
+	// Replacing the dynamic config with the same config, like the provider
+	// does on update, keeps the extra keys
+	rt.SetDynamic(&Dynamic{
+		Pools: map[string]DynamicPool{
+			"us": {Fallback: "eu", Values: []DynamicPoolValue{{Value: "3.3.3.3", Weight: 2}}},
+			"eu": {Values: []DynamicPoolValue{{Value: "2.2.2.2"}}},
+		},
+		Rules: []DynamicRule{{Geos: []string{"NA-US"}, Pool: "us"}, {Pool: "eu"}},
+	})
+	if err = sub.UpdateYaml(); err != nil {
+		t.Fatalf("UpdateYaml throws an error: %s", err)
+	}
+	if out, err = xZone.WriteYaml(); err != nil {
+		t.Fatalf("WriteYaml error: %s", err)
+	}
+	if diff := cmp.Diff(dynamicExtrasZone, string(out)); diff != "" {
+		t.Errorf("unexpected output after SetDynamic (-want +got):\n%s", diff)
+	}
+}
+
+func TestDynamic_Validate(t *testing.T) {
+
+	pool := func(fallback string, values ...string) DynamicPool {
+		p := DynamicPool{Fallback: fallback}
+		for _, v := range values {
+			p.Values = append(p.Values, DynamicPoolValue{Value: v})
+		}
+		return p
+	}
+
+	cases := []struct {
+		name    string
+		rtype   string
+		dynamic Dynamic
+		wantErr string
+	}{
+		{"valid", TYPE_A.String(), Dynamic{
+			Pools: map[string]DynamicPool{"one": pool("two", ipv4), "two": pool("", ipv4)},
+			Rules: []DynamicRule{{Pool: "one", Subnets: []string{"10.0.0.0/8"}}, {Pool: "two"}},
+		}, ""},
+		{"unsupported type", TYPE_MX.String(), Dynamic{}, "only supported"},
+		{"no pools", TYPE_A.String(), Dynamic{Rules: []DynamicRule{{Pool: "one"}}}, "at least one pool"},
+		{"invalid value", TYPE_AAAA.String(), Dynamic{
+			Pools: map[string]DynamicPool{"one": pool("", ipv4)},
+			Rules: []DynamicRule{{Pool: "one"}},
+		}, "invalid value"},
+		{"undefined rule pool", TYPE_A.String(), Dynamic{
+			Pools: map[string]DynamicPool{"one": pool("", ipv4)},
+			Rules: []DynamicRule{{Pool: "two"}},
+		}, "undefined pool two"},
+		{"undefined fallback", TYPE_A.String(), Dynamic{
+			Pools: map[string]DynamicPool{"one": pool("two", ipv4)},
+			Rules: []DynamicRule{{Pool: "one"}},
+		}, "undefined pool two"},
+		{"fallback loop", TYPE_CNAME.String(), Dynamic{
+			Pools: map[string]DynamicPool{"one": pool("two", fqdn), "two": pool("one", fqdn)},
+			Rules: []DynamicRule{{Pool: "one"}},
+		}, "one -> two -> one"},
+		{"catch all not last", TYPE_A.String(), Dynamic{
+			Pools: map[string]DynamicPool{"one": pool("", ipv4), "two": pool("", ipv4)},
+			Rules: []DynamicRule{{Pool: "one"}, {Pool: "two", Geos: []string{"EU"}}},
+		}, "only the last rule"},
+		{"invalid geo", TYPE_A.String(), Dynamic{
+			Pools: map[string]DynamicPool{"one": pool("", ipv4)},
+			Rules: []DynamicRule{{Pool: "one", Geos: []string{"US"}}},
+		}, "invalid geo"},
+		{"unused pool", TYPE_A.String(), Dynamic{
+			Pools: map[string]DynamicPool{"one": pool("", ipv4), "two": pool("", ipv4)},
+			Rules: []DynamicRule{{Pool: "one"}},
+		}, "unused pools: two"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.dynamic.Validate(c.rtype)
+			if c.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if c.wantErr != "" && (err == nil || !strings.Contains(err.Error(), c.wantErr)) {
+				t.Errorf("expected error containing %q, got %v", c.wantErr, err)
+			}
+		})
+	}
+}
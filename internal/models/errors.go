@@ -3,6 +3,9 @@ package models
 import "errors"
 
 var (
+	ErrZoneNotFound      = errors.New("zone file not found")
+	ErrZoneAlreadyExists = errors.New("zone file already exists")
+
 	ErrSubdomainNotFound      = errors.New("subdomain not found in zone")
 	ErrSubdomainAlreadyExists = errors.New("subdomain already exists in zone")
 
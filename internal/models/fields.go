@@ -0,0 +1,218 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type ValueFieldKind string
+
+const (
+	VALUE_FIELD_STRING ValueFieldKind = "string"
+	VALUE_FIELD_INT    ValueFieldKind = "int"
+	VALUE_FIELD_FLOAT  ValueFieldKind = "float"
+)
+
+// ValueField is a field of a structured record value, named after its key
+// in the zone file.
+type ValueField struct {
+	Name        string
+	Kind        ValueFieldKind
+	Required    bool
+	Description string
+}
+
+// VALUE_FIELDS holds the fields of the record types with structured values,
+// record types with plain string values have none.
+var VALUE_FIELDS = map[string][]ValueField{
+	TYPE_CAA.String(): {
+		{Name: "flags", Kind: VALUE_FIELD_STRING, Required: true, Description: "Flags, 0 or 128 for issuer critical"},
+		{Name: "tag", Kind: VALUE_FIELD_STRING, Required: true, Description: "Property tag, eq: issue, issuewild, issuevmc, issuemail or iodef"},
+		{Name: "value", Kind: VALUE_FIELD_STRING, Required: true, Description: "Issuer domain, optionally followed by `; key=value` parameters, or `;` to forbid issuance. A mailto, http or https url for iodef"},
+	},
+	TYPE_DS.String(): {
+		{Name: "key_tag", Kind: VALUE_FIELD_INT, Required: true, Description: "Key tag of the DNSKEY"},
+		{Name: "algorithm", Kind: VALUE_FIELD_INT, Required: true, Description: "Algorithm of the DNSKEY"},
+		{Name: "digest_type", Kind: VALUE_FIELD_INT, Required: true, Description: "Digest type: 1 (SHA-1), 2 (SHA-256), 3 (GOST) or 4 (SHA-384)"},
+		{Name: "digest", Kind: VALUE_FIELD_STRING, Required: true, Description: "Digest in hex"},
+	},
+	TYPE_LOC.String(): {
+		{Name: "lat_degrees", Kind: VALUE_FIELD_INT, Required: true, Description: "Latitude degrees"},
+		{Name: "lat_minutes", Kind: VALUE_FIELD_INT, Description: "Latitude minutes"},
+		{Name: "lat_seconds", Kind: VALUE_FIELD_FLOAT, Description: "Latitude seconds"},
+		{Name: "lat_direction", Kind: VALUE_FIELD_STRING, Required: true, Description: "Latitude direction: N or S"},
+		{Name: "long_degrees", Kind: VALUE_FIELD_INT, Required: true, Description: "Longitude degrees"},
+		{Name: "long_minutes", Kind: VALUE_FIELD_INT, Description: "Longitude minutes"},
+		{Name: "long_seconds", Kind: VALUE_FIELD_FLOAT, Description: "Longitude seconds"},
+		{Name: "long_direction", Kind: VALUE_FIELD_STRING, Required: true, Description: "Longitude direction: E or W"},
+		{Name: "altitude", Kind: VALUE_FIELD_FLOAT, Required: true, Description: "Altitude in meters"},
+		{Name: "size", Kind: VALUE_FIELD_INT, Description: "Size in meters"},
+		{Name: "precision_horz", Kind: VALUE_FIELD_INT, Description: "Horizontal precision in meters"},
+		{Name: "precision_vert", Kind: VALUE_FIELD_INT, Description: "Vertical precision in meters"},
+	},
+	TYPE_MX.String(): {
+		{Name: "preference", Kind: VALUE_FIELD_INT, Required: true, Description: "Preference, lower is preferred"},
+		{Name: "exchange", Kind: VALUE_FIELD_STRING, Required: true, Description: "Mail server fqdn, ending with a dot"},
+	},
+	TYPE_NAPTR.String(): {
+		{Name: "order", Kind: VALUE_FIELD_INT, Required: true, Description: "Order, lower is processed first"},
+		{Name: "preference", Kind: VALUE_FIELD_INT, Required: true, Description: "Preference between records with the same order"},
+		{Name: "flags", Kind: VALUE_FIELD_STRING, Required: true, Description: "Flags: S, A, U or P"},
+		{Name: "service", Kind: VALUE_FIELD_STRING, Required: true, Description: "Service"},
+		{Name: "regexp", Kind: VALUE_FIELD_STRING, Required: true, Description: "Substitution expression"},
+		{Name: "replacement", Kind: VALUE_FIELD_STRING, Required: true, Description: "Replacement fqdn, ending with a dot, or ."},
+	},
+	TYPE_SRV.String(): {
+		{Name: "priority", Kind: VALUE_FIELD_INT, Required: true, Description: "Priority, lower is preferred"},
+		{Name: "weight", Kind: VALUE_FIELD_INT, Required: true, Description: "Weight between records with the same priority"},
+		{Name: "port", Kind: VALUE_FIELD_INT, Required: true, Description: "Port of the service"},
+		{Name: "target", Kind: VALUE_FIELD_STRING, Required: true, Description: "Target fqdn, ending with a dot, or IP"},
+	},
+	TYPE_SSHFP.String(): {
+		{Name: "algorithm", Kind: VALUE_FIELD_INT, Required: true, Description: "Key algorithm: 1 (RSA), 2 (DSA), 3 (ECDSA) or 4 (Ed25519)"},
+		{Name: "fingerprint_type", Kind: VALUE_FIELD_INT, Required: true, Description: "Fingerprint type: 1 (SHA-1) or 2 (SHA-256)"},
+		{Name: "fingerprint", Kind: VALUE_FIELD_STRING, Required: true, Description: "Fingerprint in hex"},
+	},
+	TYPE_TLSA.String(): {
+		{Name: "certificate_usage", Kind: VALUE_FIELD_INT, Required: true, Description: "Certificate usage: 0 (PKIX-TA), 1 (PKIX-EE), 2 (DANE-TA) or 3 (DANE-EE)"},
+		{Name: "selector", Kind: VALUE_FIELD_INT, Required: true, Description: "Selector: 0 (full certificate) or 1 (public key)"},
+		{Name: "matching_type", Kind: VALUE_FIELD_INT, Required: true, Description: "Matching type: 0 (exact), 1 (SHA-256) or 2 (SHA-512)"},
+		{Name: "certificate_association_data", Kind: VALUE_FIELD_STRING, Required: true, Description: "Certificate association data in hex"},
+	},
+	TYPE_URLFWD.String(): {
+		{Name: "code", Kind: VALUE_FIELD_INT, Required: true, Description: "Redirect code: 0, 301 or 302"},
+		{Name: "masking", Kind: VALUE_FIELD_INT, Required: true, Description: "Masking: 0, 1 or 2"},
+		{Name: "path", Kind: VALUE_FIELD_STRING, Required: true, Description: "Path to forward"},
+		{Name: "query", Kind: VALUE_FIELD_INT, Required: true, Description: "Forward the query string: 0 or 1"},
+		{Name: "target", Kind: VALUE_FIELD_STRING, Required: true, Description: "Target http or https url"},
+	},
+}
+
+// AllValueFields returns the fields of all record types sorted by name, none
+// of them required. Fields shared by record types have the same kind.
+func AllValueFields() []ValueField {
+	seen := map[string]bool{}
+	fields := []ValueField{}
+	for _, typeFields := range VALUE_FIELDS {
+		for _, field := range typeFields {
+			if seen[field.Name] {
+				continue
+			}
+			seen[field.Name] = true
+			field.Required = false
+			field.Description = ""
+			fields = append(fields, field)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields
+}
+
+// NewRecordValueFromFields returns a value of the record type from its
+// fields, keyed by field name.
+func NewRecordValueFromFields(rtype string, fields map[string]interface{}) (value RecordValue, err error) {
+	known, ok := VALUE_FIELDS[rtype]
+	if !ok {
+		return value, fmt.Errorf("%s records have no structured values", rtype)
+	}
+
+	for name := range fields {
+		found := false
+		for _, field := range known {
+			found = found || field.Name == name
+		}
+		if !found {
+			return value, fmt.Errorf("%s is not a field of %s records", name, rtype)
+		}
+	}
+	missing := []string{}
+	for _, field := range known {
+		if _, ok := fields[field.Name]; field.Required && !ok {
+			missing = append(missing, field.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return value, fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+
+	node := &yaml.Node{}
+	if err = node.Encode(fields); err != nil {
+		return
+	}
+	if err = node.Decode(&value.baseRecordValue); err != nil {
+		return
+	}
+
+	return value, value.validateFields(rtype)
+}
+
+// Fields returns the fields of a structured value keyed by field name. MX
+// values written with priority and value are returned as preference and
+// exchange.
+func (r *RecordValue) Fields(rtype string) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if r.StringValue != nil {
+		return nil, fmt.Errorf("value %q is not structured", *r.StringValue)
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(r.baseRecordValue); err != nil {
+		return nil, err
+	}
+	if err := node.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	if rtype == TYPE_MX.String() && r.Preference == nil && r.Priority != nil {
+		fields["preference"] = fields["priority"]
+		fields["exchange"] = fields["value"]
+		delete(fields, "priority")
+		delete(fields, "value")
+	}
+	return fields, nil
+}
+
+// validateFields checks the fields of a structured value, like the string
+// parsers do for the string form.
+func (r *RecordValue) validateFields(rtype string) error {
+	switch rtype {
+	case TYPE_CAA.String():
+		return r.validateCAA()
+	case TYPE_DS.String():
+		return r.validateDS()
+	case TYPE_LOC.String():
+		return r.validateLOC()
+	case TYPE_MX.String():
+		return r.validateFQDN(*r.Exchange, true)
+	case TYPE_NAPTR.String():
+		return r.validateNAPTR()
+	case TYPE_SRV.String():
+		if r.validateFQDN(*r.Target, true) != nil && r.validateIP(*r.Target) != nil {
+			return fmt.Errorf("target must be a FQDN or IP")
+		}
+	case TYPE_SSHFP.String():
+		return r.validateSSHFP()
+	case TYPE_TLSA.String():
+		return r.validateTLSA()
+	case TYPE_URLFWD.String():
+		return r.validateURLFWD()
+	}
+	return nil
+}
+
+// AddValueFromFields adds a structured value to the record.
+func (r *Record) AddValueFromFields(fields map[string]interface{}) error {
+	value, err := NewRecordValueFromFields(r.Type, fields)
+	if err == nil {
+		r.Values = append(r.Values, value)
+	}
+	return err
+}
+
+func ValidateValueFields(rtype string, fields map[string]interface{}) error {
+	_, err := NewRecordValueFromFields(rtype, fields)
+	return err
+}
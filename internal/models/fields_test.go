@@ -0,0 +1,131 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecord_AddValueFromFields(t *testing.T) {
+
+	cases := []struct {
+		name   string
+		rtype  string
+		fields map[string]interface{}
+		want   string
+	}{
+		{"MX", TYPE_MX.String(), map[string]interface{}{"preference": 10, "exchange": "mx." + fqdn}, "10 mx." + fqdn},
+		{"SRV", TYPE_SRV.String(), map[string]interface{}{"priority": 10, "weight": 5, "port": 443, "target": "srv." + fqdn}, "10 5 443 srv." + fqdn},
+		{"CAA", TYPE_CAA.String(), map[string]interface{}{"flags": "0", "tag": "issue", "value": "letsencrypt.org"}, "0 issue letsencrypt.org"},
+		{"SSHFP", TYPE_SSHFP.String(), map[string]interface{}{"algorithm": 1, "fingerprint_type": 1, "fingerprint": "bf6b6825d2977c511a475bbefb88aad54a92ac73"}, "1 1 bf6b6825d2977c511a475bbefb88aad54a92ac73"},
+		{"TLSA", TYPE_TLSA.String(), map[string]interface{}{"certificate_usage": 3, "selector": 1, "matching_type": 1, "certificate_association_data": "385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1"}, "3 1 1 385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1"},
+		{"DS", TYPE_DS.String(), map[string]interface{}{"key_tag": 60485, "algorithm": 5, "digest_type": 1, "digest": "2bb183af5f22588179a53b0a98631fad1a292118"}, "60485 5 1 2bb183af5f22588179a53b0a98631fad1a292118"},
+		{"URLFWD", TYPE_URLFWD.String(), map[string]interface{}{"code": 301, "masking": 2, "path": "/", "query": 0, "target": "http://www.unit.tests"}, "301 2 / 0 http://www.unit.tests"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := &Record{BaseRecord: BaseRecord{Type: c.rtype}}
+			if err := r.AddValueFromFields(c.fields); err != nil {
+				t.Fatalf("AddValueFromFields failed: %s", err)
+			}
+			if got := r.ValuesAsString(); len(got) != 1 || got[0] != c.want {
+				t.Errorf("expected %q, got %q", c.want, got)
+			}
+
+			// The string form gives the same fields back
+			s := &Record{BaseRecord: BaseRecord{Type: c.rtype}}
+			if err := s.AddValueFromString(c.want); err != nil {
+				t.Fatalf("AddValueFromString failed: %s", err)
+			}
+			got, err := s.Values[0].Fields(c.rtype)
+			if err != nil {
+				t.Fatalf("Fields failed: %s", err)
+			}
+			if diff := cmp.Diff(c.fields, got); diff != "" {
+				t.Errorf("unexpected fields (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRecordValue_Fields_MXPriority(t *testing.T) {
+
+	rt, err := getType("mx", TYPE_MX)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	got, err := rt.Values[3].Fields(TYPE_MX.String())
+	if err != nil {
+		t.Fatalf("Fields failed: %s", err)
+	}
+	want := map[string]interface{}{"preference": 10, "exchange": "smtp-4." + fqdn}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected fields (-want +got):\n%s", diff)
+	}
+
+	if _, err = (&RecordValue{baseRecordValue{StringValue: refString(ipv4)}}).Fields(TYPE_A.String()); err == nil {
+		t.Errorf("expected an error for a string value")
+	}
+}
+
+func TestValidateValueFields(t *testing.T) {
+
+	cases := []struct {
+		name    string
+		rtype   string
+		fields  map[string]interface{}
+		wantErr bool
+	}{
+		{"A has no fields", TYPE_A.String(), map[string]interface{}{"value": ipv4}, true},
+		{"MX unknown field", TYPE_MX.String(), map[string]interface{}{"preference": 10, "exchange": fqdn, "weight": 1}, true},
+		{"MX missing exchange", TYPE_MX.String(), map[string]interface{}{"preference": 10}, true},
+		{"MX exchange without dot", TYPE_MX.String(), map[string]interface{}{"preference": 10, "exchange": fqdnNoDot}, true},
+		{"SRV target ip", TYPE_SRV.String(), map[string]interface{}{"priority": 1, "weight": 1, "port": 1, "target": ipv4}, false},
+		{"CAA flags out of range", TYPE_CAA.String(), map[string]interface{}{"flags": "129", "tag": "issue", "value": "ca.tests"}, true},
+		{"CAA arbitrary tag", TYPE_CAA.String(), map[string]interface{}{"flags": "0", "tag": "other", "value": "ca.tests"}, false},
+		{"CAA invalid tag", TYPE_CAA.String(), map[string]interface{}{"flags": "0", "tag": "is-sue", "value": "ca.tests"}, true},
+		{"CAA iodef without mailto", TYPE_CAA.String(), map[string]interface{}{"flags": "0", "tag": "iodef", "value": "ca.tests"}, true},
+		{"URLFWD invalid code", TYPE_URLFWD.String(), map[string]interface{}{"code": 200, "masking": 0, "path": "/", "query": 0, "target": "http://unit.tests"}, true},
+		{"URLFWD path with slash", TYPE_URLFWD.String(), map[string]interface{}{"code": 301, "masking": 0, "path": "/path/", "query": 0, "target": "http://unit.tests"}, true},
+		{"TLSA invalid usage", TYPE_TLSA.String(), map[string]interface{}{"certificate_usage": 4, "selector": 1, "matching_type": 0, "certificate_association_data": "00"}, true},
+		{"DS digest length", TYPE_DS.String(), map[string]interface{}{"key_tag": 1, "algorithm": 13, "digest_type": 2, "digest": "2bb183af5f22588179a53b0a98631fad1a292118"}, true},
+		{"URLFWD invalid target", TYPE_URLFWD.String(), map[string]interface{}{"code": 301, "masking": 0, "path": "/", "query": 0, "target": "unit.tests"}, true},
+		{"SSHFP invalid algorithm", TYPE_SSHFP.String(), map[string]interface{}{"algorithm": 0, "fingerprint_type": 1, "fingerprint": "bf6b6825d2977c511a475bbefb88aad54a92ac73"}, true},
+		{"NAPTR invalid flags", TYPE_NAPTR.String(), map[string]interface{}{"order": 10, "preference": 10, "flags": "X", "service": "SIP+D2U", "regexp": "!^.*$!sip:info@unit.tests!", "replacement": "."}, true},
+		{"LOC invalid minutes", TYPE_LOC.String(), map[string]interface{}{"lat_degrees": 31, "lat_minutes": 60, "lat_direction": "S", "long_degrees": 106, "long_direction": "W", "altitude": 10}, true},
+		{"LOC valid", TYPE_LOC.String(), map[string]interface{}{"lat_degrees": 31, "lat_direction": "S", "long_degrees": 106, "long_minutes": 58, "long_seconds": 2.5, "long_direction": "W", "altitude": 10.5}, false},
+		{"LOC invalid direction", TYPE_LOC.String(), map[string]interface{}{"lat_degrees": 31, "lat_direction": "E", "long_degrees": 106, "long_direction": "W", "altitude": 10}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateValueFields(c.rtype, c.fields)
+			if c.wantErr && err == nil {
+				t.Errorf("expected error for %s %v, got nil", c.rtype, c.fields)
+			}
+			if !c.wantErr && err != nil {
+				t.Errorf("unexpected error for %s %v: %s", c.rtype, c.fields, err)
+			}
+		})
+	}
+}
+
+func TestAllValueFields(t *testing.T) {
+	kinds := map[string]ValueFieldKind{}
+	for rtype, fields := range VALUE_FIELDS {
+		if _, ok := TYPES[rtype]; !ok {
+			t.Errorf("%s is not a record type", rtype)
+		}
+		for _, f := range fields {
+			if kind, ok := kinds[f.Name]; ok && kind != f.Kind {
+				t.Errorf("field %s has kind %s and %s", f.Name, kind, f.Kind)
+			}
+			kinds[f.Name] = f.Kind
+		}
+	}
+	if got := len(AllValueFields()); got != len(kinds) {
+		t.Errorf("expected %d fields, got %d", len(kinds), got)
+	}
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// GEO_TYPES are the record types octoDNS supports the legacy geo config for.
+var GEO_TYPES = []string{TYPE_A.String(), TYPE_AAAA.String()}
+
+func SupportsGeo(rtype string) bool {
+	return slices.Contains(GEO_TYPES, rtype)
+}
+
+var regGeoCode = regexp.MustCompile(`^(AF|AN|AS|EU|NA|OC|SA)(-[A-Z]{2}(-[A-Z0-9]{1,3})?)?$`)
+
+// ValidateGeoCode checks a geo code is a continent, optionally followed by a
+// country and a province, eq: NA, NA-US or NA-US-CA.
+func ValidateGeoCode(code string) error {
+	if !regGeoCode.MatchString(code) {
+		return fmt.Errorf("invalid geo %q, expected continent[-country[-province]], eq: NA-US-CA", code)
+	}
+	return nil
+}
+
+// ValidateGeo checks the `geo:` config of a record: the keys are geo codes
+// and the values are valid values of the record type.
+func ValidateGeo(rtype string, geo map[string][]string) error {
+	if !SupportsGeo(rtype) {
+		return fmt.Errorf("geo is only supported for %s records", strings.Join(GEO_TYPES, ", "))
+	}
+
+	codes := make([]string, 0, len(geo))
+	for code := range geo {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if err := ValidateGeoCode(code); err != nil {
+			return err
+		}
+		if len(geo[code]) == 0 {
+			return fmt.Errorf("geo %s has no values", code)
+		}
+		for _, value := range geo[code] {
+			if err := ValidateValueString(rtype, value); err != nil {
+				return fmt.Errorf("geo %s: invalid value %q: %w", code, value, err)
+			}
+		}
+	}
+	return nil
+}
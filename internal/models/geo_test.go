@@ -0,0 +1,120 @@
+package models
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecord_Read_Geo(t *testing.T) {
+
+	rt, err := getType("", TYPE_A)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	want := map[string][]string{
+		"AF":       {"2.2.3.4", "2.2.3.5"},
+		"AS-JP":    {"3.2.3.4", "3.2.3.5"},
+		"NA-US":    {"4.2.3.4", "4.2.3.5"},
+		"NA-US-CA": {"5.2.3.4", "5.2.3.5"},
+	}
+	if diff := cmp.Diff(want, rt.Geo); diff != "" {
+		t.Errorf("unexpected geo (-want +got):\n%s", diff)
+	}
+	if err = ValidateGeo(rt.Type, rt.Geo); err != nil {
+		t.Errorf("ValidateGeo throws an error: %s", err)
+	}
+}
+
+func TestRecord_Write_Geo(t *testing.T) {
+
+	xZone := Zone{}
+	err := xZone.ReadYaml([]byte(`www:
+  geo:
+    NA-US:
+      - 2.2.2.2
+    EU:
+      - 3.3.3.3
+  type: A
+  value: 1.1.1.1
+`))
+	if err != nil {
+		t.Fatalf("ReadYaml error: %s", err)
+	}
+	sub, err := xZone.FindSubdomain("www")
+	if err != nil {
+		t.Fatalf("FindSubdomain throws an error: %s", err)
+	}
+	rt, err := sub.GetType(TYPE_A.String())
+	if err != nil {
+		t.Fatalf("GetType throws an error: %s", err)
+	}
+
+	// Codes that were there keep their position, new ones are sorted in
+	rt.Geo["AS"] = []string{"4.4.4.4"}
+	rt.Geo["EU"] = []string{"5.5.5.5", "6.6.6.6"}
+	if err = sub.UpdateYaml(); err != nil {
+		t.Fatalf("UpdateYaml throws an error: %s", err)
+	}
+	out, err := xZone.WriteYaml()
+	if err != nil {
+		t.Fatalf("WriteYaml error: %s", err)
+	}
+
+	want := `www:
+  geo:
+    AS:
+      - 4.4.4.4
+    NA-US:
+      - 2.2.2.2
+    EU:
+      - 5.5.5.5
+      - 6.6.6.6
+  type: A
+  value: 1.1.1.1
+`
+	if !bytes.Equal(out, []byte(want)) {
+		t.Errorf("Output is not equal:\n%s", cmp.Diff(want, string(out)))
+	}
+
+	rt.Geo = nil
+	if err = sub.UpdateYaml(); err != nil {
+		t.Fatalf("UpdateYaml throws an error: %s", err)
+	}
+	if out, _ = xZone.WriteYaml(); strings.Contains(string(out), "geo") {
+		t.Errorf("expected geo to be removed, got:\n%s", out)
+	}
+}
+
+func TestValidateGeo(t *testing.T) {
+
+	cases := []struct {
+		name    string
+		rtype   string
+		geo     map[string][]string
+		wantErr bool
+	}{
+		{"continent", TYPE_A.String(), map[string][]string{"AF": {ipv4}}, false},
+		{"province", TYPE_AAAA.String(), map[string][]string{"NA-US-CA": {ipv6}}, false},
+		{"unsupported type", TYPE_CNAME.String(), map[string][]string{"AF": {fqdn}}, true},
+		{"country without continent", TYPE_A.String(), map[string][]string{"US": {ipv4}}, true},
+		{"lower case", TYPE_A.String(), map[string][]string{"na-us": {ipv4}}, true},
+		{"no values", TYPE_A.String(), map[string][]string{"EU": {}}, true},
+		{"invalid value", TYPE_A.String(), map[string][]string{"EU": {ipv6}}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateGeo(c.rtype, c.geo)
+			if c.wantErr && err == nil {
+				t.Errorf("expected error for %v, got nil", c.geo)
+			}
+			if !c.wantErr && err != nil {
+				t.Errorf("unexpected error for %v: %s", c.geo, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,441 @@
+package models
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v55/github"
+	"golang.org/x/oauth2"
+)
+
+const DEFAULT_GITHUB_URL = "https://api.github.com/"
+
+type GitHubClient struct {
+	baseClient
+	*github.Client
+	Owner string
+	Repo  string
+
+	// app is set when authenticated as a GitHub App
+	app bool
+}
+
+// NewGitHubClient returns a client for the repo on github.com, or on the
+// GitHub Enterprise Server at baseURL. The uploadURL defaults to the host of
+// the baseURL.
+func NewGitHubClient(accessToken, baseURL, uploadURL, owner, repo string, retryLimit int) (GitClient, error) {
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: accessToken},
+	)
+	client, err := newGitHubClient(ts, baseURL, uploadURL, owner, repo, retryLimit)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// NewGitHubAppClient returns a client for the repo authenticated as an
+// installation of a GitHub App, its installation tokens are refreshed before
+// they expire.
+func NewGitHubAppClient(app GitHubApp, baseURL, uploadURL, owner, repo string, retryLimit int) (GitClient, error) {
+	ts, err := NewGitHubAppTokenSource(app, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newGitHubClient(ts, baseURL, uploadURL, owner, repo, retryLimit)
+	if err != nil {
+		return nil, err
+	}
+	client.app = true
+	return client, nil
+}
+
+func newGitHubClient(ts oauth2.TokenSource, baseURL, uploadURL, owner, repo string, retryLimit int) (*GitHubClient, error) {
+
+	ctx := context.Background()
+	tc := oauth2.NewClient(ctx, ts)
+
+	gh, err := newGitHubAPIClient(tc, baseURL, uploadURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &GitHubClient{
+		Client: gh,
+		Owner:  owner,
+		Repo:   repo,
+	}
+	client.init(client, retryLimit)
+
+	return client, nil
+
+}
+
+// newGitHubAPIClient returns a go-github client for github.com or the GitHub
+// Enterprise Server at baseURL.
+func newGitHubAPIClient(httpClient *http.Client, baseURL, uploadURL string) (*github.Client, error) {
+	gh := github.NewClient(httpClient)
+	if IsGitHubDotCom(baseURL) {
+		return gh, nil
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid github url %q", baseURL)
+	}
+	if uploadURL == "" {
+		uploadURL = u.Scheme + "://" + u.Host + "/"
+	}
+	if gh, err = gh.WithEnterpriseURLs(baseURL, uploadURL); err != nil {
+		return nil, fmt.Errorf("invalid github upload url %q: %w", uploadURL, err)
+	}
+	return gh, nil
+}
+
+// IsGitHubDotCom reports if the API url is the one of github.com, which is
+// also the case when it is empty.
+func IsGitHubDotCom(baseURL string) bool {
+	if baseURL == "" {
+		return true
+	}
+	u, err := url.Parse(baseURL)
+	return err == nil && (u.Host == "github.com" || u.Host == "api.github.com")
+}
+
+func (g *GitHubClient) fetchZone(ctx context.Context, zone, scope string) (*Zone, error) {
+	return g.getZoneFile(ctx, zone, scope, "")
+}
+
+// revalidateZone uses a conditional request with the ETag of the cached zone,
+// GitHub doesn't count 304 Not Modified responses against the rate limit.
+func (g *GitHubClient) revalidateZone(ctx context.Context, cached *Zone) (*Zone, error) {
+	return g.getZoneFile(ctx, cached.name, cached.scope, cached.etag)
+}
+
+// getZoneFile loads the zone file using the contents API, when etag is set
+// nil is returned if the file still matches it.
+func (g *GitHubClient) getZoneFile(ctx context.Context, zone, scope, etag string) (*Zone, error) {
+	sc, err := g.GetScope(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	branch, err := g.zoneBranch(ctx, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	// GetContents can't send the If-None-Match header
+	path := (&url.URL{Path: sc.CreateFilePath(zone)}).String()
+	query := url.Values{"ref": []string{branch}}
+	req, err := g.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/contents/%s?%s", g.Owner, g.Repo, path, query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	fileContent := &github.RepositoryContent{}
+	response, err := g.Do(ctx, req, fileContent)
+	switch {
+	case response != nil && response.StatusCode == http.StatusNotModified:
+		return nil, nil
+	case response != nil && response.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrZoneNotFound, sc.CreateFilePath(zone))
+	case err != nil:
+		return nil, err
+	}
+
+	contents, err := fileContent.GetContent()
+	if err != nil {
+		return nil, err
+	}
+
+	z := Zone{}
+	z.name = zone
+	z.scope = scope
+	z.sha = fileContent.GetSHA()
+	z.etag = response.Header.Get("ETag")
+
+	err = z.ReadYaml([]byte(contents))
+	if err != nil {
+		return nil, err
+	}
+	return &z, nil
+}
+
+// saveZones commits the zones in a single commit using the git data API. A
+// tree with the new zone files is committed on top of the branch head, and
+// the branch is only moved to the new commit when that is a fast-forward,
+// so either every zone lands or none does.
+func (g *GitHubClient) saveZones(ctx context.Context, zones []*Zone, comment string) error {
+	scope, err := g.GetScope(zones[0].scope)
+	if err != nil {
+		return err
+	}
+
+	branch, err := g.zoneBranch(ctx, scope)
+	if err != nil {
+		return err
+	}
+
+	ref, response, err := g.Git.GetRef(ctx, g.Owner, g.Repo, "heads/"+branch)
+	if err != nil {
+		return g.retryableError(response, err)
+	}
+	parent, response, err := g.Git.GetCommit(ctx, g.Owner, g.Repo, ref.GetObject().GetSHA())
+	if err != nil {
+		return g.retryableError(response, err)
+	}
+
+	files := make([]githubFile, 0, len(zones))
+	for _, zone := range zones {
+		sc, err := g.GetScope(zone.scope)
+		if err != nil {
+			return err
+		}
+		filepath := sc.CreateFilePath(zone.name)
+
+		// The zone must still be the version we read, as the new tree
+		// replaces the file regardless of what it contains at the parent.
+		current, _, response, err := g.Repositories.GetContents(ctx, g.Owner, g.Repo, filepath, &github.RepositoryContentGetOptions{Ref: parent.GetSHA()})
+		switch {
+		case zone.created && response != nil && response.StatusCode == http.StatusNotFound:
+		case zone.created && err == nil:
+			return &RetryableError{Err: fmt.Errorf("%s was created since it was checked", filepath), Conflict: true}
+		case err != nil:
+			return g.retryableError(response, err)
+		case current.GetSHA() != zone.sha:
+			return &RetryableError{Err: fmt.Errorf("%s changed since it was read", filepath), Conflict: true}
+		}
+
+		file := githubFile{path: filepath, deleted: zone.deleted}
+		if !zone.deleted {
+			if file.content, err = zone.WriteYaml(); err != nil {
+				return err
+			}
+		}
+		files = append(files, file)
+	}
+
+	if g.CommitSigning != nil && g.CommitSigning.GraphQL {
+		if err = g.commitOnBranch(ctx, branch, parent.GetSHA(), files, comment); err != nil {
+			return err
+		}
+		setBlobSHAs(zones, files)
+		return nil
+	}
+
+	entries := make([]*github.TreeEntry, 0, len(files))
+	for _, file := range files {
+		entry := &github.TreeEntry{
+			Path: github.String(file.path),
+			Mode: github.String("100644"),
+			Type: github.String("blob"),
+		}
+		// an entry without content or sha removes the file
+		if !file.deleted {
+			entry.Content = github.String(string(file.content))
+		}
+		entries = append(entries, entry)
+	}
+
+	tree, response, err := g.Git.CreateTree(ctx, g.Owner, g.Repo, parent.GetTree().GetSHA(), entries)
+	if err != nil {
+		return g.retryableError(response, err)
+	}
+
+	var author *github.CommitAuthor = nil
+	if g.AuthorName != "" || g.AuthorEmail != "" {
+		author = &github.CommitAuthor{}
+		if g.AuthorName != "" {
+			author.Name = github.String(g.AuthorName)
+		}
+		if g.AuthorEmail != "" {
+			author.Email = github.String(g.AuthorEmail)
+		}
+	}
+
+	newCommit := &github.Commit{
+		Message:   github.String(comment),
+		Tree:      tree,
+		Parents:   []*github.Commit{{SHA: parent.SHA}},
+		Author:    author,
+		Committer: author,
+	}
+	if g.CommitSigning != nil && g.CommitSigning.Signer != nil {
+		// The date is part of the signed payload, so it is set by us
+		date := time.Now().UTC().Truncate(time.Second)
+		author.Date = &github.Timestamp{Time: date}
+		payload := commitPayload(tree.GetSHA(), []string{parent.GetSHA()}, g.AuthorName, g.AuthorEmail, date, comment)
+		signature, err := g.CommitSigning.Signer.Sign(payload)
+		if err != nil {
+			return fmt.Errorf("could not sign commit: %w", err)
+		}
+		newCommit.Verification = &github.SignatureVerification{Signature: github.String(signature)}
+	}
+
+	commit, response, err := g.Git.CreateCommit(ctx, g.Owner, g.Repo, newCommit)
+	if err != nil {
+		return g.retryableError(response, err)
+	}
+
+	ref.Object = &github.GitObject{SHA: commit.SHA}
+	_, response, err = g.Git.UpdateRef(ctx, g.Owner, g.Repo, ref, false)
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && response.StatusCode == http.StatusUnprocessableEntity && strings.Contains(errResp.Message, "fast forward") {
+		return &RetryableError{Err: err, Conflict: true}
+	}
+	if err != nil {
+		return g.retryableError(response, err)
+	}
+	setBlobSHAs(zones, files)
+	return nil
+}
+
+// setBlobSHAs sets the sha of the committed zones to the blob sha of their
+// new contents, which is the sha the contents API returns for them.
+func setBlobSHAs(zones []*Zone, files []githubFile) {
+	for i, file := range files {
+		if !file.deleted {
+			zones[i].sha = gitBlobSHA(file.content)
+		}
+	}
+}
+
+// gitBlobSHA returns the object id git gives a file with the content.
+func gitBlobSHA(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (g *GitHubClient) findPullRequest(ctx context.Context, head, base string) (*PullRequest, error) {
+	options := &github.PullRequestListOptions{
+		State: "open",
+		Head:  g.Owner + ":" + head,
+		Base:  base,
+	}
+
+	prs, _, err := g.PullRequests.List(ctx, g.Owner, g.Repo, options)
+	if err != nil || len(prs) == 0 {
+		return nil, err
+	}
+	return &PullRequest{
+		Number: prs[0].GetNumber(),
+		URL:    prs[0].GetHTMLURL(),
+		Body:   prs[0].GetBody(),
+	}, nil
+}
+
+func (g *GitHubClient) resetBranch(ctx context.Context, head, base string) error {
+	baseRef, _, err := g.Git.GetRef(ctx, g.Owner, g.Repo, "heads/"+base)
+	if err != nil {
+		return err
+	}
+
+	ref := &github.Reference{
+		Ref:    github.String("refs/heads/" + head),
+		Object: &github.GitObject{SHA: baseRef.Object.SHA},
+	}
+
+	_, response, err := g.Git.GetRef(ctx, g.Owner, g.Repo, "heads/"+head)
+	if response != nil && response.StatusCode == http.StatusNotFound {
+		_, _, err = g.Git.CreateRef(ctx, g.Owner, g.Repo, ref)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	_, _, err = g.Git.UpdateRef(ctx, g.Owner, g.Repo, ref, true)
+	return err
+}
+
+// openPullRequest opens the pull request, then adds the labels and requests
+// the reviews as those can't be set when creating it.
+func (g *GitHubClient) openPullRequest(ctx context.Context, head, base string, pr *PullRequest) error {
+	options := &github.NewPullRequest{
+		Title: github.String(g.PullRequest.Title),
+		Head:  github.String(head),
+		Base:  github.String(base),
+		Body:  github.String(pr.Body),
+		Draft: github.Bool(g.PullRequest.Draft),
+	}
+
+	created, _, err := g.PullRequests.Create(ctx, g.Owner, g.Repo, options)
+	if err != nil {
+		return err
+	}
+	pr.Number = created.GetNumber()
+	pr.URL = created.GetHTMLURL()
+
+	if len(g.PullRequest.Labels) > 0 {
+		if _, _, err = g.Issues.AddLabelsToIssue(ctx, g.Owner, g.Repo, pr.Number, g.PullRequest.Labels); err != nil {
+			return err
+		}
+	}
+
+	if len(g.PullRequest.Reviewers) > 0 || len(g.PullRequest.TeamReviewers) > 0 {
+		reviewers := github.ReviewersRequest{
+			Reviewers:     g.PullRequest.Reviewers,
+			TeamReviewers: g.PullRequest.TeamReviewers,
+		}
+		if _, _, err = g.PullRequests.RequestReviewers(ctx, g.Owner, g.Repo, pr.Number, reviewers); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *GitHubClient) updatePullRequest(ctx context.Context, pr *PullRequest) error {
+	_, _, err := g.PullRequests.Edit(ctx, g.Owner, g.Repo, pr.Number, &github.PullRequest{Body: github.String(pr.Body)})
+	return err
+}
+
+// retryableError wraps errors of GitHub calls that may succeed when retried.
+// Secondary rate limits and exhausted rate limits carry the wait time GitHub
+// asks for, conflicts and server errors use the default backoff.
+func (g *GitHubClient) retryableError(response *github.Response, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		retryErr := &RetryableError{Err: err}
+		if abuseErr.RetryAfter != nil {
+			retryErr.Delay = *abuseErr.RetryAfter
+		}
+		return retryErr
+	}
+
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return &RetryableError{Err: err, Delay: time.Until(rateErr.Rate.Reset.Time)}
+	}
+
+	if response == nil {
+		return err
+	}
+
+	switch {
+	case response.StatusCode == http.StatusConflict:
+		return &RetryableError{Err: err, Conflict: true}
+	case response.StatusCode >= http.StatusInternalServerError:
+		return &RetryableError{Err: err}
+	}
+
+	return err
+}
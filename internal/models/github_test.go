@@ -0,0 +1,58 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestNewGitHubClient_URLs(t *testing.T) {
+	tests := []struct {
+		name      string
+		baseURL   string
+		uploadURL string
+		wantBase  string
+		wantUp    string
+	}{
+		{"github.com", "", "", "https://api.github.com/", "https://uploads.github.com/"},
+		{"github.com api", "https://api.github.com/", "", "https://api.github.com/", "https://uploads.github.com/"},
+		{"enterprise", "https://github.example.com", "", "https://github.example.com/api/v3/", "https://github.example.com/api/uploads/"},
+		{"enterprise api path", "https://github.example.com/api/v3/", "", "https://github.example.com/api/v3/", "https://github.example.com/api/uploads/"},
+		{"enterprise upload url", "https://github.example.com/", "https://uploads.example.com/", "https://github.example.com/api/v3/", "https://uploads.example.com/api/uploads/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewGitHubClient("token", tt.baseURL, tt.uploadURL, "org", "repo", 3)
+			if err != nil {
+				t.Fatalf("NewGitHubClient failed: %s", err)
+			}
+			gh := client.(*GitHubClient)
+			if got := gh.BaseURL.String(); got != tt.wantBase {
+				t.Errorf("BaseURL = %s, want %s", got, tt.wantBase)
+			}
+			if got := gh.UploadURL.String(); got != tt.wantUp {
+				t.Errorf("UploadURL = %s, want %s", got, tt.wantUp)
+			}
+		})
+	}
+
+	if _, err := NewGitHubClient("token", "://github.example.com", "", "org", "repo", 3); err == nil {
+		t.Errorf("expected an error for an invalid url")
+	}
+	if _, err := NewGitHubClient("token", "github.example.com", "", "org", "repo", 3); err == nil {
+		t.Errorf("expected an error for an url without scheme")
+	}
+}
+
+func TestIsGitHubDotCom(t *testing.T) {
+	tests := map[string]bool{
+		"":                            true,
+		"https://api.github.com/":     true,
+		"https://github.com":          true,
+		"https://github.example.com/": false,
+		"https://api.example.com/":    false,
+	}
+	for baseURL, want := range tests {
+		if got := IsGitHubDotCom(baseURL); got != want {
+			t.Errorf("IsGitHubDotCom(%q) = %v, want %v", baseURL, got, want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v55/github"
+	"golang.org/x/oauth2"
+)
+
+// GitHubApp holds the credentials of an installation of a GitHub App, used
+// to mint installation tokens.
+type GitHubApp struct {
+	AppID          int64
+	InstallationID int64
+	PrivateKey     []byte // PEM encoded RSA key
+}
+
+// GITHUB_APP_TOKEN_TIMEOUT limits how long minting an installation token
+// takes, the token source is called without the context of the request.
+const GITHUB_APP_TOKEN_TIMEOUT = 30 * time.Second
+
+// gitHubAppTokenSource mints installation tokens of a GitHub App, signing
+// the requests with a JWT of the app.
+type gitHubAppTokenSource struct {
+	app     GitHubApp
+	key     *rsa.PrivateKey
+	client  *github.Client
+	timeout time.Duration
+}
+
+// NewGitHubAppTokenSource returns a token source of the installation tokens
+// of the app, on github.com or the GitHub Enterprise Server at baseURL.
+// Tokens are refreshed a minute before they expire.
+func NewGitHubAppTokenSource(app GitHubApp, baseURL string) (oauth2.TokenSource, error) {
+	if app.AppID <= 0 || app.InstallationID <= 0 {
+		return nil, fmt.Errorf("github app id and installation id are required")
+	}
+	key, err := parseGitHubAppKey(app.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newGitHubAPIClient(nil, baseURL, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ts := &gitHubAppTokenSource{app: app, key: key, client: client, timeout: GITHUB_APP_TOKEN_TIMEOUT}
+	return oauth2.ReuseTokenSourceWithExpiry(nil, ts, time.Minute), nil
+}
+
+// parseGitHubAppKey parses the PEM encoded private key of an app, as PKCS1
+// like GitHub hands them out or as PKCS8.
+func parseGitHubAppKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("github app private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("github app private key is invalid: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("github app private key should be an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// jwt returns a JWT authenticating as the app, valid for ten minutes, the
+// maximum GitHub allows. It is issued a minute in the past to allow for
+// clock drift.
+func (s *gitHubAppTokenSource) jwt(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(s.app.AppID, 10),
+	})
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Token mints a new installation token, giving up after the timeout.
+func (s *gitHubAppTokenSource) Token() (*oauth2.Token, error) {
+	jwt, err := s.jwt(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("could not sign github app jwt: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	token, _, err := s.client.WithAuthToken(jwt).Apps.CreateInstallationToken(ctx, s.app.InstallationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create github app installation token: %w", err)
+	}
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		TokenType:   "Bearer",
+		Expiry:      token.GetExpiresAt().Time,
+	}, nil
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newGitHubAppKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %s", err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// verifyAppJWT checks the signature of a JWT of the app and returns its
+// claims.
+func verifyAppJWT(t *testing.T, key *rsa.PrivateKey, jwt string) map[string]interface{} {
+	t.Helper()
+
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("jwt %q should have 3 parts", jwt)
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hash[:], signature); err != nil {
+		t.Fatalf("jwt signature is invalid: %s", err)
+	}
+
+	claims := map[string]interface{}{}
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("jwt claims are invalid: %s", err)
+	}
+	return claims
+}
+
+func TestGitHubApp_Token(t *testing.T) {
+	key, keyPEM := newGitHubAppKey(t)
+
+	var mu sync.Mutex
+	minted := 0
+	expiry := time.Now().Add(time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v3/app/installations/42/access_tokens" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		claims := verifyAppJWT(t, key, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if claims["iss"] != "7" {
+			t.Errorf("jwt iss = %v, want 7", claims["iss"])
+		}
+		if exp, iat := claims["exp"].(float64), claims["iat"].(float64); exp-iat > 600 {
+			t.Errorf("jwt is valid for %v seconds, more than 10 minutes", exp-iat)
+		}
+
+		minted++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"token":"ghs_%d","expires_at":%q}`, minted, expiry.Format(time.RFC3339))
+	}))
+	t.Cleanup(server.Close)
+
+	ts, err := NewGitHubAppTokenSource(GitHubApp{AppID: 7, InstallationID: 42, PrivateKey: keyPEM}, server.URL)
+	if err != nil {
+		t.Fatalf("NewGitHubAppTokenSource failed: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		token, err := ts.Token()
+		if err != nil {
+			t.Fatalf("Token failed: %s", err)
+		}
+		if token.AccessToken != "ghs_1" {
+			t.Errorf("AccessToken = %s, want ghs_1", token.AccessToken)
+		}
+	}
+	if minted != 1 {
+		t.Errorf("minted %d tokens, want the first one to be reused", minted)
+	}
+
+	// Tokens about to expire are refreshed
+	expiry = time.Now().Add(30 * time.Second)
+	ts, _ = NewGitHubAppTokenSource(GitHubApp{AppID: 7, InstallationID: 42, PrivateKey: keyPEM}, server.URL)
+	first, _ := ts.Token()
+	second, err := ts.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %s", err)
+	}
+	if first.AccessToken == second.AccessToken {
+		t.Errorf("token %s expiring within a minute should be refreshed", first.AccessToken)
+	}
+}
+
+func TestGitHubApp_TokenTimeout(t *testing.T) {
+	key, _ := newGitHubAppKey(t)
+
+	// the server doesn't answer before the test is done
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(done) })
+
+	client, err := newGitHubAPIClient(nil, server.URL, "")
+	if err != nil {
+		t.Fatalf("newGitHubAPIClient failed: %s", err)
+	}
+	ts := &gitHubAppTokenSource{app: GitHubApp{AppID: 7, InstallationID: 42}, key: key, client: client, timeout: 50 * time.Millisecond}
+
+	start := time.Now()
+	if _, err = ts.Token(); err == nil {
+		t.Fatalf("expected the token request to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("token request took %s, expected it to give up after the timeout", elapsed)
+	}
+}
+
+func TestGitHubApp_Invalid(t *testing.T) {
+	_, keyPEM := newGitHubAppKey(t)
+
+	tests := map[string]GitHubApp{
+		"missing app id":          {InstallationID: 42, PrivateKey: keyPEM},
+		"missing installation id": {AppID: 7, PrivateKey: keyPEM},
+		"missing key":             {AppID: 7, InstallationID: 42},
+		"not pem":                 {AppID: 7, InstallationID: 42, PrivateKey: []byte("secret")},
+		"invalid key":             {AppID: 7, InstallationID: 42, PrivateKey: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("secret")})},
+	}
+	for name, app := range tests {
+		if _, err := NewGitHubAppTokenSource(app, ""); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
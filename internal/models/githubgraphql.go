@@ -0,0 +1,127 @@
+package models
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// githubFile is a file changed by a commit, deleted files have no content.
+type githubFile struct {
+	path    string
+	content []byte
+	deleted bool
+}
+
+// checkCommitSigning checks the commit signing options, GitHub only signs
+// commits made through the GraphQL API when they are made by an app.
+func (g *GitHubClient) checkCommitSigning(options *CommitSigningOptions) error {
+	if options.GraphQL && !g.app {
+		return fmt.Errorf("commits are only signed by github when authenticated as a github app")
+	}
+	return nil
+}
+
+// graphQLURL returns the url of the GraphQL API, next to the REST API.
+func (g *GitHubClient) graphQLURL() string {
+	u := *g.BaseURL
+	if strings.HasSuffix(u.Path, "/api/v3/") {
+		u.Path = strings.TrimSuffix(u.Path, "v3/") + "graphql"
+	} else {
+		u.Path += "graphql"
+	}
+	return u.String()
+}
+
+const createCommitOnBranchMutation = `mutation($input: CreateCommitOnBranchInput!) {
+  createCommitOnBranch(input: $input) {
+    commit {
+      oid
+    }
+  }
+}`
+
+type graphQLResponse struct {
+	Data struct {
+		CreateCommitOnBranch struct {
+			Commit struct {
+				Oid string `json:"oid"`
+			} `json:"commit"`
+		} `json:"createCommitOnBranch"`
+	} `json:"data"`
+	Errors []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// commitOnBranch commits the files on top of the head of the branch using
+// the createCommitOnBranch mutation. GitHub signs these commits, so they show
+// as verified. The author is the app, the configured author is not used.
+func (g *GitHubClient) commitOnBranch(ctx context.Context, branch, head string, files []githubFile, comment string) error {
+	additions := []map[string]string{}
+	deletions := []map[string]string{}
+	for _, file := range files {
+		if file.deleted {
+			deletions = append(deletions, map[string]string{"path": file.path})
+			continue
+		}
+		additions = append(additions, map[string]string{
+			"path":     file.path,
+			"contents": base64.StdEncoding.EncodeToString(file.content),
+		})
+	}
+
+	headline, body, _ := strings.Cut(comment, "\n")
+	input := map[string]interface{}{
+		"branch": map[string]string{
+			"repositoryNameWithOwner": g.Owner + "/" + g.Repo,
+			"branchName":              branch,
+		},
+		"message": map[string]string{
+			"headline": headline,
+			"body":     strings.TrimSpace(body),
+		},
+		"expectedHeadOid": head,
+		"fileChanges": map[string]interface{}{
+			"additions": additions,
+			"deletions": deletions,
+		},
+	}
+
+	req, err := g.NewRequest("POST", g.graphQLURL(), map[string]interface{}{
+		"query":     createCommitOnBranchMutation,
+		"variables": map[string]interface{}{"input": input},
+	})
+	if err != nil {
+		return err
+	}
+
+	result := &graphQLResponse{}
+	response, err := g.Do(ctx, req, result)
+	if err != nil {
+		return g.retryableError(response, err)
+	}
+
+	for _, e := range result.Errors {
+		switch {
+		// The branch moved since its head was read
+		case e.Type == "STALE_DATA" || strings.Contains(e.Message, "Expected branch to point to"):
+			return &RetryableError{Err: fmt.Errorf("graphql: %s", e.Message), Conflict: true}
+		case e.Type == "RATE_LIMITED":
+			return &RetryableError{Err: fmt.Errorf("graphql: %s", e.Message)}
+		}
+	}
+	if len(result.Errors) > 0 {
+		messages := []string{}
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("graphql: %s", strings.Join(messages, ", "))
+	}
+	if result.Data.CreateCommitOnBranch.Commit.Oid == "" {
+		return fmt.Errorf("graphql: no commit was created")
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DEFAULT_GITLAB_URL = "https://gitlab.com/api/v4"
+
+// GitLabClient reads and writes zone files using the GitLab repository
+// files and commits API.
+type GitLabClient struct {
+	baseClient
+	HTTPClient *http.Client
+	BaseURL    string
+	Project    string
+	token      string
+}
+
+// GitLabError is returned for every non successful GitLab API response.
+type GitLabError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *GitLabError) Error() string {
+	return fmt.Sprintf("gitlab api error %d: %s", e.StatusCode, e.Message)
+}
+
+type gitlabFile struct {
+	FilePath     string `json:"file_path"`
+	Encoding     string `json:"encoding"`
+	Content      string `json:"content"`
+	LastCommitID string `json:"last_commit_id"`
+}
+
+type gitlabCommitAction struct {
+	Action       string `json:"action"`
+	FilePath     string `json:"file_path"`
+	Content      string `json:"content,omitempty"`
+	Encoding     string `json:"encoding,omitempty"`
+	LastCommitID string `json:"last_commit_id,omitempty"`
+}
+
+type gitlabCommit struct {
+	Branch        string               `json:"branch"`
+	CommitMessage string               `json:"commit_message"`
+	AuthorName    string               `json:"author_name,omitempty"`
+	AuthorEmail   string               `json:"author_email,omitempty"`
+	Actions       []gitlabCommitAction `json:"actions"`
+}
+
+// NewGitLabClient returns a client for the project, which can either be the
+// numeric project ID or its full path like `group/dns`.
+func NewGitLabClient(accessToken, baseURL, project string, retryLimit int) (GitClient, error) {
+
+	if baseURL == "" {
+		baseURL = DEFAULT_GITLAB_URL
+	}
+	if _, err := url.Parse(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid gitlab url %q: %w", baseURL, err)
+	}
+	if project == "" {
+		return nil, fmt.Errorf("gitlab project is required")
+	}
+
+	client := &GitLabClient{
+		HTTPClient: http.DefaultClient,
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Project:    project,
+		token:      accessToken,
+	}
+	client.init(client, retryLimit)
+
+	return client, nil
+}
+
+func (g *GitLabClient) fetchZone(ctx context.Context, zone, scope string) (*Zone, error) {
+	sc, err := g.GetScope(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	branch, err := g.zoneBranch(ctx, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"ref": []string{branch}}
+	file := gitlabFile{}
+	err = g.do(ctx, http.MethodGet, "repository/files/"+url.PathEscape(sc.CreateFilePath(zone))+"?"+query.Encode(), nil, &file)
+	var glErr *GitLabError
+	if errors.As(err, &glErr) && glErr.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrZoneNotFound, sc.CreateFilePath(zone))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contents := []byte(file.Content)
+	if file.Encoding == "base64" {
+		if contents, err = base64.StdEncoding.DecodeString(file.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	z := Zone{}
+	z.name = zone
+	z.scope = scope
+	z.sha = file.LastCommitID
+
+	err = z.ReadYaml(contents)
+	if err != nil {
+		return nil, err
+	}
+	return &z, nil
+}
+
+// saveZones commits the zones in a single commit using the commits API. The
+// last commit ID every file was read at is sent along, so GitLab rejects the
+// whole commit when someone else changed one of the files in the meantime.
+// Creating a file that already exists is rejected as well.
+func (g *GitLabClient) saveZones(ctx context.Context, zones []*Zone, comment string) error {
+	scope, err := g.GetScope(zones[0].scope)
+	if err != nil {
+		return err
+	}
+
+	branch, err := g.zoneBranch(ctx, scope)
+	if err != nil {
+		return err
+	}
+
+	commit := gitlabCommit{
+		Branch:        branch,
+		CommitMessage: comment,
+		AuthorName:    g.AuthorName,
+		AuthorEmail:   g.AuthorEmail,
+		Actions:       make([]gitlabCommitAction, 0, len(zones)),
+	}
+
+	for _, zone := range zones {
+		sc, err := g.GetScope(zone.scope)
+		if err != nil {
+			return err
+		}
+		action := gitlabCommitAction{
+			Action:       "update",
+			FilePath:     sc.CreateFilePath(zone.name),
+			LastCommitID: zone.sha,
+		}
+		switch {
+		case zone.deleted:
+			action.Action = "delete"
+			commit.Actions = append(commit.Actions, action)
+			continue
+		case zone.created:
+			action.Action = "create"
+			action.LastCommitID = ""
+		}
+
+		content, err := zone.WriteYaml()
+		if err != nil {
+			return err
+		}
+		action.Content = base64.StdEncoding.EncodeToString(content)
+		action.Encoding = "base64"
+		commit.Actions = append(commit.Actions, action)
+	}
+
+	result := struct {
+		ID string `json:"id"`
+	}{}
+	if err = g.do(ctx, http.MethodPost, "repository/commits", commit, &result); err != nil {
+		return g.retryableError(err)
+	}
+	// the last commit id of every written file is now that of our commit
+	for _, zone := range zones {
+		zone.sha = result.ID
+	}
+	return nil
+}
+
+// retryableError wraps errors of GitLab calls that may succeed when retried.
+func (g *GitLabClient) retryableError(err error) error {
+	var glErr *GitLabError
+	if !errors.As(err, &glErr) {
+		return err
+	}
+
+	switch {
+	case glErr.StatusCode == http.StatusConflict:
+		return &RetryableError{Err: err, Conflict: true}
+	case glErr.StatusCode == http.StatusBadRequest && strings.Contains(glErr.Message, "changed since"),
+		glErr.StatusCode == http.StatusBadRequest && strings.Contains(glErr.Message, "already exists"):
+		return &RetryableError{Err: err, Conflict: true}
+	case glErr.StatusCode == http.StatusTooManyRequests:
+		return &RetryableError{Err: err, Delay: glErr.RetryAfter}
+	case glErr.StatusCode >= http.StatusInternalServerError:
+		return &RetryableError{Err: err}
+	}
+
+	return err
+}
+
+// do performs a request against the project API, body and result are
+// encoded to and decoded from json when not nil.
+func (g *GitLabClient) do(ctx context.Context, method, path string, body, result interface{}) error {
+	endpoint := fmt.Sprintf("%s/projects/%s/%s", g.BaseURL, url.PathEscape(g.Project), path)
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("PRIVATE-TOKEN", g.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		glErr := &GitLabError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+
+		var message struct {
+			Message interface{} `json:"message"`
+			Error   string      `json:"error"`
+		}
+		if json.Unmarshal(data, &message) == nil {
+			if message.Message != nil {
+				glErr.Message = fmt.Sprintf("%v", message.Message)
+			} else if message.Error != "" {
+				glErr.Message = message.Error
+			}
+		}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			glErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return glErr
+	}
+
+	if result != nil {
+		return json.Unmarshal(data, result)
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package models
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeGitLab is a minimal stand-in for the GitLab repository files and
+// commits API of a single project.
+type fakeGitLab struct {
+	mu       sync.Mutex
+	files    map[string]string
+	commits  map[string]string // last commit id per file
+	posts    []gitlabCommit
+	failures []int // status codes returned for the next commit requests
+	headers  []http.Header
+}
+
+func newFakeGitLab(t *testing.T, files map[string]string) (*fakeGitLab, *httptest.Server) {
+	t.Helper()
+
+	fake := &fakeGitLab{files: map[string]string{}, commits: map[string]string{}}
+	for k, v := range files {
+		fake.setFile(k, v)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(fake.ServeHTTP))
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeGitLab) setFile(path, content string) {
+	f.files[path] = content
+	f.commits[path] = fmt.Sprintf("commit-%d", len(f.commits)+len(f.posts)+1)
+}
+
+func (f *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Header.Get("PRIVATE-TOKEN") != "token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"401 Unauthorized"}`))
+		return
+	}
+
+	// EscapedPath keeps the encoded slashes of the project and file path
+	path := r.URL.EscapedPath()
+	prefix := "/api/v4/projects/group%2Fdns/repository/"
+	if !strings.HasPrefix(path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"404 Project Not Found"}`))
+		return
+	}
+	path = strings.TrimPrefix(path, prefix)
+
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "files/"):
+		file := strings.ReplaceAll(strings.TrimPrefix(path, "files/"), "%2F", "/")
+		content, ok := f.files[file]
+		if !ok || r.URL.Query().Get("ref") != "main" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"404 File Not Found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(gitlabFile{
+			FilePath:     file,
+			Encoding:     "base64",
+			Content:      base64.StdEncoding.EncodeToString([]byte(content)),
+			LastCommitID: f.commits[file],
+		})
+	case r.Method == http.MethodPost && path == "commits":
+		commit := gitlabCommit{}
+		_ = json.NewDecoder(r.Body).Decode(&commit)
+		f.posts = append(f.posts, commit)
+
+		if len(f.failures) > 0 {
+			status := f.failures[0]
+			f.failures = f.failures[1:]
+			if len(f.headers) > 0 {
+				for k, v := range f.headers[0] {
+					w.Header()[k] = v
+				}
+				f.headers = f.headers[1:]
+			}
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"message":"failure"}`))
+			return
+		}
+
+		for _, action := range commit.Actions {
+			if action.LastCommitID != "" && action.LastCommitID != f.commits[action.FilePath] {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"message":"You are attempting to update a file that has changed since you started editing it."}`))
+				return
+			}
+			if _, ok := f.files[action.FilePath]; ok && action.Action == "create" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"message":"A file with this name already exists"}`))
+				return
+			}
+		}
+		id := fmt.Sprintf("commit-%d", len(f.commits)+len(f.posts)+1)
+		for _, action := range commit.Actions {
+			if action.Action == "delete" {
+				delete(f.files, action.FilePath)
+				delete(f.commits, action.FilePath)
+				continue
+			}
+			content, _ := base64.StdEncoding.DecodeString(action.Content)
+			f.files[action.FilePath] = string(content)
+			f.commits[action.FilePath] = id
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"id":%q}`, id)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"404 Not Found"}`))
+	}
+}
+
+func newFakeGitLabClient(t *testing.T, server *httptest.Server) *GitLabClient {
+	t.Helper()
+
+	client, err := NewGitLabClient("token", server.URL+"/api/v4/", "group/dns", 3)
+	if err != nil {
+		t.Fatalf("NewGitLabClient failed: %s", err)
+	}
+	gl := client.(*GitLabClient)
+	gl.RetryDelay = 0
+	gl.BatchWindow = time.Millisecond
+
+	if err = gl.AddScope("default", "zones", "", "yaml"); err != nil {
+		t.Fatalf("AddScope failed: %s", err)
+	}
+	_ = gl.SetAuthor("octodns", "octodns@example.com")
+	return gl
+}
+
+func TestGitLab_GetZone(t *testing.T) {
+	_, server := newFakeGitLab(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitLabClient(t, server)
+
+	zone, err := client.GetZone(context.Background(), "example.com", "default")
+	if err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	rt, err := zone.GetRecord("www", TYPE_A.String())
+	if err != nil {
+		t.Fatalf("GetRecord failed: %s", err)
+	}
+	validateStringValues(t, rt, []string{"1.1.1.1"})
+
+	if _, err = client.GetZone(context.Background(), "missing.com", "default"); err == nil {
+		t.Errorf("expected an error for a missing zone file")
+	}
+}
+
+func TestGitLab_SaveZone(t *testing.T) {
+	fake, server := newFakeGitLab(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitLabClient(t, server)
+
+	if err := editRecord(t, client, "new", "2.2.2.2"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.posts) != 1 {
+		t.Fatalf("expected 1 commit, got %d", len(fake.posts))
+	}
+	commit := fake.posts[0]
+	if commit.Branch != "main" || commit.AuthorName != "octodns" || commit.AuthorEmail != "octodns@example.com" {
+		t.Errorf("unexpected commit options: %+v", commit)
+	}
+	if commit.CommitMessage != "chore(default/example.com): create A record for new" {
+		t.Errorf("unexpected commit message: %q", commit.CommitMessage)
+	}
+	want := "www:\n  type: A\n  value: 1.1.1.1\nnew:\n  - ttl: 300\n    type: A\n    value: 2.2.2.2\n"
+	if got := fake.files["zones/example.com.yaml"]; got != want {
+		t.Errorf("unexpected file content (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestGitLab_SaveZoneKeptCached(t *testing.T) {
+	fake, server := newFakeGitLab(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitLabClient(t, server)
+
+	for _, subdomain := range []string{"new", "other"} {
+		if err := editRecord(t, client, subdomain, "2.2.2.2"); err != nil {
+			t.Fatalf("flush failed: %s", err)
+		}
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.posts) != 2 {
+		t.Errorf("expected 2 commit requests without conflict, got %d", len(fake.posts))
+	}
+}
+
+func TestGitLab_ConflictReplaysChanges(t *testing.T) {
+	fake, server := newFakeGitLab(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitLabClient(t, server)
+
+	if _, err := client.GetZone(context.Background(), "example.com", "default"); err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	fake.mu.Lock()
+	fake.setFile("zones/example.com.yaml", "other:\n  type: A\n  value: 3.3.3.3\n")
+	fake.mu.Unlock()
+
+	if err := editRecord(t, client, "new", "2.2.2.2"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.posts) != 2 {
+		t.Errorf("expected 2 commit requests, got %d", len(fake.posts))
+	}
+	want := "other:\n  type: A\n  value: 3.3.3.3\nnew:\n  - ttl: 300\n    type: A\n    value: 2.2.2.2\n"
+	if got := fake.files["zones/example.com.yaml"]; got != want {
+		t.Errorf("unexpected file content (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestGitLab_RetryRateLimit(t *testing.T) {
+	fake, server := newFakeGitLab(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitLabClient(t, server)
+	fake.failures = []int{http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusForbidden}
+	fake.headers = []http.Header{{"Retry-After": []string{"0"}}}
+
+	err := editRecord(t, client, "new", "2.2.2.2")
+	if err == nil {
+		t.Fatalf("expected the 403 to fail the flush")
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.posts) != 3 {
+		t.Errorf("expected 3 commit requests, got %d", len(fake.posts))
+	}
+}
+
+func TestGitLab_SaveZonesSingleCommit(t *testing.T) {
+	fake, server := newFakeGitLab(t, map[string]string{
+		"zones/example.com.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+		"zones/example.org.yaml": "www:\n  type: A\n  value: 1.1.1.1\n",
+	})
+	client := newFakeGitLabClient(t, server)
+
+	if err := editZones(t, client, "new", "2.2.2.2", "example.com", "example.org"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.posts) != 1 {
+		t.Fatalf("expected 1 commit, got %d", len(fake.posts))
+	}
+	if len(fake.posts[0].Actions) != 2 {
+		t.Errorf("expected 2 actions in the commit, got %d", len(fake.posts[0].Actions))
+	}
+	if fake.posts[0].CommitMessage != "chore: 2 changes in 2 zones (2 creates)" {
+		t.Errorf("unexpected commit message: %q", fake.posts[0].CommitMessage)
+	}
+	want := "www:\n  type: A\n  value: 1.1.1.1\nnew:\n  - ttl: 300\n    type: A\n    value: 2.2.2.2\n"
+	for _, file := range []string{"zones/example.com.yaml", "zones/example.org.yaml"} {
+		if got := fake.files[file]; got != want {
+			t.Errorf("unexpected content of %s (-want +got):\n%s", file, cmp.Diff(want, got))
+		}
+	}
+}
+
+func TestGitLab_CreateAndDeleteZone(t *testing.T) {
+	fake, server := newFakeGitLab(t, map[string]string{})
+	client := newFakeGitLabClient(t, server)
+
+	if err := createZone(t, client, "example.org"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+	if err := deleteZone(t, client, "example.org"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.posts) != 2 {
+		t.Fatalf("expected 2 commits, got %d", len(fake.posts))
+	}
+	if action := fake.posts[0].Actions[0]; action.Action != "create" || action.LastCommitID != "" {
+		t.Errorf("unexpected create action: %+v", action)
+	}
+	if action := fake.posts[1].Actions[0]; action.Action != "delete" {
+		t.Errorf("unexpected delete action: %+v", action)
+	}
+	if _, ok := fake.files["zones/example.org.yaml"]; ok {
+		t.Errorf("expected the zone file to be removed")
+	}
+}
@@ -0,0 +1,321 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// LocalClient reads and writes zone files in a directory on disk, like a
+// checked out working copy of the dns repository. When Commit is set every
+// save is committed to the checked out branch using go-git, nothing is pushed.
+type LocalClient struct {
+	baseClient
+	Dir    string
+	Commit bool
+	repo   *git.Repository
+}
+
+// NewLocalClient returns a client for the zone files below dir. When commit is
+// set, dir must be (inside) a git working copy.
+func NewLocalClient(dir string, commit bool, retryLimit int) (GitClient, error) {
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("local path %q is not a directory", dir)
+	}
+
+	client := &LocalClient{
+		Dir:    dir,
+		Commit: commit,
+	}
+	client.init(client, retryLimit)
+
+	if commit {
+		client.repo, err = git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+		if err != nil {
+			return nil, fmt.Errorf("could not open git repository at %q: %w", dir, err)
+		}
+	}
+
+	return client, nil
+}
+
+func (l *LocalClient) fetchZone(_ context.Context, zone, scope string) (*Zone, error) {
+	sc, err := l.GetScope(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := os.ReadFile(filepath.Join(l.Dir, filepath.FromSlash(sc.CreateFilePath(zone))))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrZoneNotFound, sc.CreateFilePath(zone))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	z := Zone{}
+	z.name = zone
+	z.scope = scope
+	z.sha = contentHash(contents)
+
+	err = z.ReadYaml(contents)
+	if err != nil {
+		return nil, err
+	}
+	return &z, nil
+}
+
+// saveZones writes the zone files and commits them when Commit is set. Like
+// the remote providers, nothing is written when one of the files changed on
+// disk since it was read, so the pending changes are replayed on the new
+// contents. When writing or committing fails, the zone files are restored so
+// either every zone lands or none does.
+func (l *LocalClient) saveZones(ctx context.Context, zones []*Zone, comment string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	scope, err := l.GetScope(zones[0].scope)
+	if err != nil {
+		return err
+	}
+
+	if l.Commit {
+		if err = l.checkBranch(scope.GetBranch(l.Branch)); err != nil {
+			return err
+		}
+	}
+
+	type write struct {
+		zone     *Zone
+		filename string
+		content  []byte
+		// original is the content on disk, restored when a write fails
+		original []byte
+		perm     os.FileMode
+	}
+
+	writes := []write{}
+	for _, zone := range zones {
+		sc, err := l.GetScope(zone.scope)
+		if err != nil {
+			return err
+		}
+		filename := filepath.Join(l.Dir, filepath.FromSlash(sc.CreateFilePath(zone.name)))
+
+		current, err := os.ReadFile(filename)
+		switch {
+		case zone.created && errors.Is(err, os.ErrNotExist):
+		case zone.created && err == nil:
+			return &RetryableError{Err: fmt.Errorf("%s was created on disk since it was checked", filename), Conflict: true}
+		case err != nil:
+			return err
+		case contentHash(current) != zone.sha:
+			return &RetryableError{Err: fmt.Errorf("%s changed on disk since it was read", filename), Conflict: true}
+		}
+
+		perm := os.FileMode(0o644)
+		if !zone.created {
+			info, err := os.Stat(filename)
+			if err != nil {
+				return err
+			}
+			perm = info.Mode().Perm()
+		}
+
+		if zone.deleted {
+			writes = append(writes, write{zone: zone, filename: filename, original: current, perm: perm})
+			continue
+		}
+
+		content, err := zone.WriteYaml()
+		if err != nil {
+			return err
+		}
+		if zone.created || !bytes.Equal(current, content) {
+			writes = append(writes, write{zone: zone, filename: filename, content: content, original: current, perm: perm})
+		}
+	}
+
+	if len(writes) == 0 {
+		return nil
+	}
+
+	// restore puts back the zone files that were already replaced
+	restore := func(written []write) {
+		for _, w := range written {
+			if w.zone.created {
+				_ = os.Remove(w.filename)
+			} else {
+				_ = os.WriteFile(w.filename, w.original, w.perm)
+			}
+		}
+	}
+
+	// The new contents are written next to the zone files first, so a
+	// failing write leaves all zone files untouched.
+	temps := make([]string, len(writes))
+	removeTemps := func() {
+		for _, temp := range temps {
+			if temp != "" {
+				_ = os.Remove(temp)
+			}
+		}
+	}
+	for i, w := range writes {
+		if w.zone.deleted {
+			continue
+		}
+		if err = os.MkdirAll(filepath.Dir(w.filename), 0o755); err != nil {
+			removeTemps()
+			return err
+		}
+		if temps[i], err = writeTemp(w.filename, w.content, w.perm); err != nil {
+			removeTemps()
+			return err
+		}
+	}
+
+	filenames := make([]string, 0, len(writes))
+	for i, w := range writes {
+		filenames = append(filenames, w.filename)
+		if w.zone.deleted {
+			err = os.Remove(w.filename)
+		} else if err = os.Rename(temps[i], w.filename); err == nil {
+			temps[i] = ""
+		}
+		if err != nil {
+			removeTemps()
+			restore(writes[:i])
+			return err
+		}
+	}
+
+	if l.Commit {
+		if err = l.commitFiles(filenames, comment); err != nil {
+			// Don't leave the changes uncommitted in the working copy
+			restore(writes)
+			_ = l.stageFiles(filenames)
+			return err
+		}
+	}
+
+	for _, w := range writes {
+		if !w.zone.deleted {
+			w.zone.sha = contentHash(w.content)
+		}
+	}
+	return nil
+}
+
+// writeTemp writes the content to a new temporary file in the directory of
+// filename and returns its name.
+func writeTemp(filename string, content []byte, perm os.FileMode) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), perm)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// checkBranch makes sure the working copy has the branch checked out that the
+// zone is configured for, as we never switch branches in someone's checkout.
+func (l *LocalClient) checkBranch(branch string) error {
+	head, err := l.repo.Head()
+	if err != nil {
+		return err
+	}
+	if !head.Name().IsBranch() || head.Name().Short() != branch {
+		return fmt.Errorf("working copy has %s checked out, expected branch %s", head.Name().Short(), branch)
+	}
+	return nil
+}
+
+// commitFiles stages the files, or their removal, and commits the index.
+// Without an author set the user from the git config is used.
+func (l *LocalClient) commitFiles(filenames []string, comment string) error {
+	if err := l.stageFiles(filenames); err != nil {
+		return err
+	}
+
+	worktree, err := l.repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	options := &git.CommitOptions{}
+	if l.AuthorName != "" || l.AuthorEmail != "" {
+		options.Author = &object.Signature{
+			Name:  l.AuthorName,
+			Email: l.AuthorEmail,
+			When:  time.Now(),
+		}
+	}
+
+	_, err = worktree.Commit(comment, options)
+	return err
+}
+
+// stageFiles adds the files, or their removal, to the index.
+func (l *LocalClient) stageFiles(filenames []string) error {
+	worktree, err := l.repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	root, err := filepath.EvalSymlinks(worktree.Filesystem.Root())
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range filenames {
+		abs, err := filepath.Abs(filename)
+		if err != nil {
+			return err
+		}
+		// resolve the directory, as deleted files are gone already
+		dir, err := filepath.EvalSymlinks(filepath.Dir(abs))
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, filepath.Join(dir, filepath.Base(abs)))
+		if err != nil {
+			return err
+		}
+
+		if _, err = worktree.Add(filepath.ToSlash(rel)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func contentHash(content []byte) string {
+	sum := sha1.Sum(content)
+	return hex.EncodeToString(sum[:])
+}
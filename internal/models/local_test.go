@@ -0,0 +1,214 @@
+package models
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-cmp/cmp"
+)
+
+const localZone = "www:\n  type: A\n  value: 1.1.1.1\n"
+
+func newLocalTestClient(t *testing.T, commit bool) (*LocalClient, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "zones"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "zones", "example.com.yaml"), []byte(localZone), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if commit {
+		repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+			InitOptions: git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName("main")},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		worktree, _ := repo.Worktree()
+		if _, err = worktree.Add("zones/example.com.yaml"); err != nil {
+			t.Fatal(err)
+		}
+		_, err = worktree.Commit("initial", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client, err := NewLocalClient(dir, commit, 3)
+	if err != nil {
+		t.Fatalf("NewLocalClient failed: %s", err)
+	}
+	local := client.(*LocalClient)
+	local.RetryDelay = 0
+	local.BatchWindow = time.Millisecond
+	_ = local.AddScope("default", "zones", "", "yaml")
+	_ = local.SetAuthor("octodns", "octodns@example.com")
+
+	return local, dir
+}
+
+func TestLocal_SaveZone(t *testing.T) {
+	client, dir := newLocalTestClient(t, false)
+
+	if err := editRecord(t, client, "new", "2.2.2.2"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	got, _ := os.ReadFile(filepath.Join(dir, "zones", "example.com.yaml"))
+	want := localZone + "new:\n  - ttl: 300\n    type: A\n    value: 2.2.2.2\n"
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("unexpected file content (-want +got):\n%s", diff)
+	}
+}
+
+func TestLocal_SaveZonesFailureLeavesFiles(t *testing.T) {
+	client, dir := newLocalTestClient(t, false)
+
+	// The zone in the other scope can't be written, as its directory is a file
+	if err := os.WriteFile(filepath.Join(dir, "blocked"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_ = client.AddScope("other", "blocked", "", "yaml")
+
+	zone, err := client.GetZone(context.Background(), "example.com", "default")
+	if err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	rt := createEmptyType("new", TYPE_A)
+	if err = rt.AddValueFromString("2.2.2.2"); err != nil {
+		t.Fatalf("AddValueFromString failed: %s", err)
+	}
+	if err = zone.ApplyChange(NewRecordUpsert("new", rt)); err != nil {
+		t.Fatalf("ApplyChange failed: %s", err)
+	}
+
+	err = client.SaveZones(context.Background(), []*Zone{zone, NewZone("example.org", "other")}, "chore: 2 changes")
+	if err == nil {
+		t.Fatalf("expected an error writing the blocked zone")
+	}
+
+	got, _ := os.ReadFile(filepath.Join(dir, "zones", "example.com.yaml"))
+	if diff := cmp.Diff(localZone, string(got)); diff != "" {
+		t.Errorf("zone file should be untouched (-want +got):\n%s", diff)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(dir, "zones")); len(entries) != 1 {
+		t.Errorf("expected no temporary files to be left, got %v", entries)
+	}
+}
+
+func TestLocal_ConflictReplaysChanges(t *testing.T) {
+	client, dir := newLocalTestClient(t, false)
+	filename := filepath.Join(dir, "zones", "example.com.yaml")
+
+	if _, err := client.GetZone(context.Background(), "example.com", "default"); err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	other := "other:\n  type: A\n  value: 3.3.3.3\n"
+	if err := os.WriteFile(filename, []byte(other), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := editRecord(t, client, "new", "2.2.2.2"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	got, _ := os.ReadFile(filename)
+	want := other + "new:\n  - ttl: 300\n    type: A\n    value: 2.2.2.2\n"
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("unexpected file content (-want +got):\n%s", diff)
+	}
+}
+
+func TestLocal_Commit(t *testing.T) {
+	client, dir := newLocalTestClient(t, true)
+
+	if err := editRecord(t, client, "new", "2.2.2.2"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, _ := repo.Head()
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.Message != "chore(default/example.com): create A record for new" {
+		t.Errorf("unexpected commit message: %q", commit.Message)
+	}
+	if commit.Author.Name != "octodns" || commit.Author.Email != "octodns@example.com" {
+		t.Errorf("unexpected commit author: %s", commit.Author)
+	}
+
+	worktree, _ := repo.Worktree()
+	status, _ := worktree.Status()
+	if !status.IsClean() {
+		t.Errorf("expected a clean working copy, got:\n%s", status)
+	}
+}
+
+func TestLocal_CommitWrongBranch(t *testing.T) {
+	client, _ := newLocalTestClient(t, true)
+	_ = client.SetBranch("production")
+
+	if err := editRecord(t, client, "new", "2.2.2.2"); err == nil {
+		t.Errorf("expected an error when the working copy has another branch checked out")
+	}
+}
+
+func TestLocal_NotARepository(t *testing.T) {
+	if _, err := NewLocalClient(t.TempDir(), true, 0); err == nil {
+		t.Errorf("expected an error when committing outside a git repository")
+	}
+}
+
+func TestLocal_CreateAndDeleteZone(t *testing.T) {
+	client, dir := newLocalTestClient(t, true)
+	filename := filepath.Join(dir, "zones", "example.org.yaml")
+
+	if err := createZone(t, client, "example.org"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+	got, _ := os.ReadFile(filename)
+	if diff := cmp.Diff(createdZone, string(got)); diff != "" {
+		t.Errorf("unexpected file content (-want +got):\n%s", diff)
+	}
+
+	if err := deleteZone(t, client, "example.org"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", filename, err)
+	}
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, _ := repo.Head()
+	commit, _ := repo.CommitObject(head.Hash())
+	if commit.Message != "chore(default/example.org): delete zone" {
+		t.Errorf("unexpected commit message: %q", commit.Message)
+	}
+	if _, err = commit.File("zones/example.org.yaml"); err == nil {
+		t.Errorf("expected the zone file to be removed from the commit")
+	}
+	worktree, _ := repo.Worktree()
+	status, _ := worktree.Status()
+	if !status.IsClean() {
+		t.Errorf("expected a clean working copy, got:\n%s", status)
+	}
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OctodnsRecordConfig is the `octodns:` key of a record, the provider specific
+// config of the record by provider key, like cloudflare or route53. The config
+// is kept as it was read, so keys that are never set are written back as they
+// were.
+type OctodnsRecordConfig struct {
+	// content holds the key and value nodes of the config, like the content
+	// of a mapping node. It is never changed in place, so copies of the config
+	// can be restored.
+	content []*yaml.Node
+}
+
+func (o *OctodnsRecordConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: octodns should be a mapping", value.Line)
+	}
+	o.content = slices.Clone(value.Content)
+	return nil
+}
+
+func (o OctodnsRecordConfig) MarshalYAML() (interface{}, error) {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: o.content}, nil
+}
+
+func (o OctodnsRecordConfig) IsZero() bool {
+	return len(o.content) == 0
+}
+
+// Keys returns the keys of the config in the order of the file.
+func (o OctodnsRecordConfig) Keys() []string {
+	keys := []string{}
+	for i := 0; i+1 < len(o.content); i += 2 {
+		keys = append(keys, o.content[i].Value)
+	}
+	return keys
+}
+
+func (o OctodnsRecordConfig) index(key string) int {
+	for i := 0; i+1 < len(o.content); i += 2 {
+		if o.content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns the decoded config of the key, mappings are returned as
+// map[string]interface{}.
+func (o OctodnsRecordConfig) Get(key string) (value interface{}, ok bool, err error) {
+	ok, err = o.decode(key, &value)
+	return value, ok, err
+}
+
+func (o OctodnsRecordConfig) decode(key string, out interface{}) (bool, error) {
+	i := o.index(key)
+	if i < 0 {
+		return false, nil
+	}
+	if err := o.content[i+1].Decode(out); err != nil {
+		return true, fmt.Errorf("octodns.%s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Set replaces the config of the key. A key that was there already keeps its
+// position and the order of its keys, new keys are inserted in front of the
+// first key that sorts after them.
+func (o *OctodnsRecordConfig) Set(key string, value interface{}) error {
+	i := o.index(key)
+
+	var old *yaml.Node
+	if i >= 0 {
+		old = o.content[i+1]
+	}
+	node, err := readOrder(old).encode(value)
+	if err != nil {
+		return fmt.Errorf("octodns.%s: %w", key, err)
+	}
+
+	content := slices.Clone(o.content)
+	if i >= 0 {
+		content[i+1] = node
+	} else {
+		pos := len(content)
+		for y := 0; y+1 < len(content); y += 2 {
+			if content[y].Value > key {
+				pos = y
+				break
+			}
+		}
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+		content = slices.Insert(content, pos, keyNode, node)
+	}
+	o.content = content
+
+	return nil
+}
+
+// Delete removes the config of the key.
+func (o *OctodnsRecordConfig) Delete(key string) {
+	if i := o.index(key); i >= 0 {
+		o.content = slices.Delete(slices.Clone(o.content), i, i+2)
+	}
+}
+
+// OCTODNS_LIFECYCLE_KEYS are the keys of the octodns config that are modelled
+// as fields of the record instead of provider config.
+var OCTODNS_LIFECYCLE_KEYS = []string{"ignored", "included", "excluded"}
+
+// readLifecycle sets the lifecycle flags of the record from its octodns
+// config.
+func (r *Record) readLifecycle() error {
+	r.Ignored, r.Included, r.Excluded = false, nil, nil
+	if _, err := r.Octodns.decode("ignored", &r.Ignored); err != nil {
+		return err
+	}
+	if _, err := r.Octodns.decode("included", &r.Included); err != nil {
+		return err
+	}
+	_, err := r.Octodns.decode("excluded", &r.Excluded)
+	return err
+}
+
+// lifecycleOctodns returns the octodns config of the record with its
+// lifecycle flags. Keys that hold the same value already are left as they
+// are, so `ignored: false` stays in the file.
+func (r Record) lifecycleOctodns() (OctodnsRecordConfig, error) {
+	config := r.Octodns
+
+	var ignored bool
+	if _, err := config.decode("ignored", &ignored); err != nil || ignored != r.Ignored {
+		if r.Ignored {
+			if err := config.Set("ignored", true); err != nil {
+				return config, err
+			}
+		} else {
+			config.Delete("ignored")
+		}
+	}
+
+	lists := map[string][]string{"included": r.Included, "excluded": r.Excluded}
+	for _, key := range []string{"included", "excluded"} {
+		var current []string
+		if _, err := config.decode(key, &current); err == nil && slices.Equal(current, lists[key]) {
+			continue
+		}
+		if len(lists[key]) == 0 {
+			config.Delete(key)
+		} else if err := config.Set(key, lists[key]); err != nil {
+			return config, err
+		}
+	}
+
+	return config, nil
+}
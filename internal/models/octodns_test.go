@@ -0,0 +1,155 @@
+package models
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v3"
+)
+
+func TestOctodnsRecordConfig_SetDelete(t *testing.T) {
+
+	var config OctodnsRecordConfig
+	err := yaml.Unmarshal([]byte(`route53:
+  healthcheck:
+    request_interval: 10
+    measure_latency: false
+# comment of cloudflare
+cloudflare:
+  proxied: true
+`), &config)
+	if err != nil {
+		t.Fatalf("Unmarshal error: %s", err)
+	}
+
+	if diff := cmp.Diff([]string{"route53", "cloudflare"}, config.Keys()); diff != "" {
+		t.Errorf("unexpected keys (-want +got):\n%s", diff)
+	}
+
+	saved := config
+
+	if err = config.Set("route53", map[string]interface{}{
+		"healthcheck": map[string]interface{}{"measure_latency": true},
+	}); err != nil {
+		t.Fatalf("Set route53: %s", err)
+	}
+	if err = config.Set("azuredns", map[string]interface{}{}); err != nil {
+		t.Fatalf("Set azuredns: %s", err)
+	}
+	if err = config.Set("ns1", map[string]interface{}{"healthcheck": map[string]interface{}{"policy": "quorum"}}); err != nil {
+		t.Fatalf("Set ns1: %s", err)
+	}
+	config.Delete("cloudflare")
+	config.Delete("unknown")
+
+	if _, ok, _ := config.Get("cloudflare"); ok {
+		t.Errorf("cloudflare should be deleted")
+	}
+
+	want := `azuredns: {}
+ns1:
+    healthcheck:
+        policy: quorum
+route53:
+    healthcheck:
+        measure_latency: true
+`
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		t.Fatalf("Marshal error: %s", err)
+	}
+	if !bytes.Equal(out, []byte(want)) {
+		t.Errorf("Output is not equal:\n%s", cmp.Diff(want, string(out)))
+	}
+
+	// The copy taken before the changes is left as it was
+	want = `route53:
+    healthcheck:
+        request_interval: 10
+        measure_latency: false
+# comment of cloudflare
+cloudflare:
+    proxied: true
+`
+	out, err = yaml.Marshal(saved)
+	if err != nil {
+		t.Fatalf("Marshal error: %s", err)
+	}
+	if !bytes.Equal(out, []byte(want)) {
+		t.Errorf("Output is not equal:\n%s", cmp.Diff(want, string(out)))
+	}
+
+}
+
+func TestRecord_UpdateYaml_Lifecycle(t *testing.T) {
+
+	xZone := Zone{}
+	err := xZone.ReadYaml([]byte(`www:
+  - octodns:
+      cloudflare:
+        proxied: true
+      excluded:
+        - route53
+      ignored: false
+    type: A
+    value: 1.1.1.1
+  - octodns:
+      ignored: true
+    type: TXT
+    value: staged
+`))
+	if err != nil {
+		t.Fatalf("ReadYaml error: %s", err)
+	}
+
+	sub, err := xZone.FindSubdomain("www")
+	if err != nil {
+		t.Fatalf("FindSubdomain throws an error: %s", err)
+	}
+	a, err := sub.GetType(TYPE_A.String())
+	if err != nil {
+		t.Fatalf("GetType throws an error: %s", err)
+	}
+	txt, err := sub.GetType(TYPE_TXT.String())
+	if err != nil {
+		t.Fatalf("GetType throws an error: %s", err)
+	}
+
+	if a.Ignored || a.Included != nil || !cmp.Equal(a.Excluded, []string{"route53"}) {
+		t.Errorf("unexpected lifecycle of A record: %v %v %v", a.Ignored, a.Included, a.Excluded)
+	}
+	if !txt.Ignored {
+		t.Errorf("TXT record should be ignored")
+	}
+
+	a.Excluded = nil
+	a.Included = []string{"azure", "cloudflare"}
+	txt.Ignored = false
+	if err = sub.UpdateYaml(); err != nil {
+		t.Fatalf("UpdateYaml throws an error: %s", err)
+	}
+
+	out, err := xZone.WriteYaml()
+	if err != nil {
+		t.Fatalf("WriteYaml error: %s", err)
+	}
+
+	want := `www:
+  - octodns:
+      cloudflare:
+        proxied: true
+      ignored: false
+      included:
+        - azure
+        - cloudflare
+    type: A
+    value: 1.1.1.1
+  - type: TXT
+    value: staged
+`
+	if !bytes.Equal(out, []byte(want)) {
+		t.Errorf("Output is not equal:\n%s", cmp.Diff(want, string(out)))
+	}
+
+}
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	CHANGE_MODE_COMMIT       = "commit"
+	CHANGE_MODE_PULL_REQUEST = "pull_request"
+
+	DEFAULT_PULL_REQUEST_BRANCH_PREFIX = "octodns/"
+	DEFAULT_PULL_REQUEST_TITLE         = "chore: update dns records"
+
+	pullRequestBodyHeader = "Changes proposed by the octodns terraform provider:\n"
+)
+
+// PullRequestOptions configures the pull requests that propose changes
+// instead of committing them to the branch directly.
+type PullRequestOptions struct {
+	// BranchPrefix is prepended to the target branch to name the feature
+	// branch, so `octodns/` proposes changes to main from `octodns/main`.
+	BranchPrefix  string
+	Title         string
+	Labels        []string
+	Reviewers     []string
+	TeamReviewers []string
+	Draft         bool
+}
+
+// head returns the feature branch the changes for base are committed to.
+func (o *PullRequestOptions) head(base string) string {
+	return o.BranchPrefix + base
+}
+
+// PullRequest is an open pull request proposing changes to a branch.
+type PullRequest struct {
+	Number int
+	URL    string
+	Body   string
+}
+
+// pullRequester is implemented by git providers that can propose changes
+// using pull requests.
+type pullRequester interface {
+	// findPullRequest returns the open pull request from head into base, or
+	// nil when there is none.
+	findPullRequest(ctx context.Context, head, base string) (*PullRequest, error)
+	// resetBranch points head at the latest commit of base, creating the
+	// branch when it doesn't exist yet.
+	resetBranch(ctx context.Context, head, base string) error
+	// openPullRequest opens a pull request from head into base with the body
+	// of pr, and sets its number and url.
+	openPullRequest(ctx context.Context, head, base string, pr *PullRequest) error
+	// updatePullRequest replaces the body of the pull request.
+	updatePullRequest(ctx context.Context, pr *PullRequest) error
+}
+
+// SetPullRequest makes the client propose changes using pull requests, pass
+// nil to commit to the branch directly.
+func (b *baseClient) SetPullRequest(options *PullRequestOptions) error {
+	var requester pullRequester
+	if options != nil {
+		var ok bool
+		if requester, ok = b.backend.(pullRequester); !ok {
+			return fmt.Errorf("pull requests are not supported by this git provider")
+		}
+		if options.BranchPrefix == "" {
+			options.BranchPrefix = DEFAULT_PULL_REQUEST_BRANCH_PREFIX
+		}
+		if options.Title == "" {
+			options.Title = DEFAULT_PULL_REQUEST_TITLE
+		}
+	}
+	b.PullRequest = options
+	b.requester = requester
+	b.pullRequests = map[string]*PullRequest{}
+	return nil
+}
+
+// zoneBranch returns the branch the zone files of the scope are read from and
+// committed to. When proposing changes that is the feature branch of the
+// pull request into the scope branch, so pending changes show up in plans,
+// or the scope branch itself while there is no open pull request.
+func (b *baseClient) zoneBranch(ctx context.Context, sc Scope) (string, error) {
+	base := sc.GetBranch(b.Branch)
+	if b.PullRequest == nil {
+		return base, nil
+	}
+
+	pr, ok := b.pullRequests[base]
+	if !ok {
+		var err error
+		pr, err = b.requester.findPullRequest(ctx, b.PullRequest.head(base), base)
+		if err != nil {
+			return "", fmt.Errorf("could not look up pull request into %s: %w", base, err)
+		}
+		b.pullRequests[base] = pr
+	}
+	if pr == nil {
+		return base, nil
+	}
+	return b.PullRequest.head(base), nil
+}
+
+// prepareBranch makes sure the feature branch for the zone exists before it
+// is committed. Without an open pull request the feature branch is reset to
+// the target branch, dropping whatever was left from earlier pull requests.
+// Returns the target branch.
+func (b *baseClient) prepareBranch(ctx context.Context, zone *Zone) (string, error) {
+	sc, err := b.GetScope(zone.scope)
+	if err != nil {
+		return "", err
+	}
+	if _, err = b.zoneBranch(ctx, sc); err != nil {
+		return "", err
+	}
+
+	base := sc.GetBranch(b.Branch)
+	if b.pullRequests[base] == nil {
+		if err = b.requester.resetBranch(ctx, b.PullRequest.head(base), base); err != nil {
+			return "", fmt.Errorf("could not create branch %s: %w", b.PullRequest.head(base), err)
+		}
+		b.pullRequests[base] = &PullRequest{}
+	}
+	return base, nil
+}
+
+// proposeChanges opens a pull request for every target branch that got new
+// commits, or adds the comments to the body of the one that is already open.
+func (b *baseClient) proposeChanges(ctx context.Context, comments map[string][]string) error {
+	bases := make([]string, 0, len(comments))
+	for base := range comments {
+		bases = append(bases, base)
+	}
+	sort.Strings(bases)
+
+	for _, base := range bases {
+		pr := b.pullRequests[base]
+		body := strings.TrimRight(pr.Body, "\n") + "\n"
+		if pr.Body == "" {
+			body = pullRequestBodyHeader + "\n"
+		}
+		for _, c := range comments[base] {
+			body += "- " + c + "\n"
+		}
+		pr.Body = body
+
+		if pr.Number == 0 {
+			if err := b.requester.openPullRequest(ctx, b.PullRequest.head(base), base, pr); err != nil {
+				return fmt.Errorf("could not open pull request into %s: %w", base, err)
+			}
+			continue
+		}
+		if err := b.requester.updatePullRequest(ctx, pr); err != nil {
+			return fmt.Errorf("could not update pull request #%d: %w", pr.Number, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newPullRequestTestClient(t *testing.T, server *httptest.Server, options *PullRequestOptions) *GitHubClient {
+	t.Helper()
+
+	client := newFakeGitHubClient(t, server)
+	if err := client.SetPullRequest(options); err != nil {
+		t.Fatalf("SetPullRequest failed: %s", err)
+	}
+	return client
+}
+
+const pullRequestZone = "www:\n  type: A\n  value: 1.1.1.1\n"
+
+func TestPullRequest_Open(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{"zones/example.com.yaml": pullRequestZone})
+	client := newPullRequestTestClient(t, server, &PullRequestOptions{
+		Labels:        []string{"dns"},
+		Reviewers:     []string{"alice"},
+		TeamReviewers: []string{"network"},
+		Draft:         true,
+	})
+
+	if err := editRecord(t, client, "new", "2.2.2.2"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if got := fake.file("main", "zones/example.com.yaml"); got != pullRequestZone {
+		t.Errorf("expected main to be untouched, got:\n%s", got)
+	}
+	want := pullRequestZone + "new:\n  - ttl: 300\n    type: A\n    value: 2.2.2.2\n"
+	if got := fake.file("octodns/main", "zones/example.com.yaml"); got != want {
+		t.Errorf("unexpected file content on feature branch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	if len(fake.pulls) != 1 {
+		t.Fatalf("expected 1 pull request, got %d", len(fake.pulls))
+	}
+	wantPull := &fakePull{
+		Number:    1,
+		State:     "open",
+		Title:     DEFAULT_PULL_REQUEST_TITLE,
+		Body:      pullRequestBodyHeader + "\n- chore(default/example.com): create A record for new\n",
+		Draft:     true,
+		Head:      "octodns/main",
+		Base:      "main",
+		Labels:    []string{"dns"},
+		Reviewers: []string{"alice", "network"},
+	}
+	if diff := cmp.Diff(wantPull, fake.pulls[0]); diff != "" {
+		t.Errorf("unexpected pull request (-want +got):\n%s", diff)
+	}
+}
+
+func TestPullRequest_UpdateOpenPullRequest(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{"zones/example.com.yaml": pullRequestZone})
+	client := newPullRequestTestClient(t, server, &PullRequestOptions{})
+
+	if err := editRecord(t, client, "one", "2.2.2.2"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	// A new run of the provider finds the open pull request
+	client = newPullRequestTestClient(t, server, &PullRequestOptions{})
+	if err := editRecord(t, client, "two", "3.3.3.3"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	want := pullRequestZone + "one:\n  - ttl: 300\n    type: A\n    value: 2.2.2.2\ntwo:\n  - ttl: 300\n    type: A\n    value: 3.3.3.3\n"
+	if got := fake.file("octodns/main", "zones/example.com.yaml"); got != want {
+		t.Errorf("unexpected file content on feature branch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	if len(fake.pulls) != 1 {
+		t.Fatalf("expected 1 pull request, got %d", len(fake.pulls))
+	}
+	wantBody := pullRequestBodyHeader + "\n" +
+		"- chore(default/example.com): create A record for one\n" +
+		"- chore(default/example.com): create A record for two\n"
+	if diff := cmp.Diff(wantBody, fake.pulls[0].Body); diff != "" {
+		t.Errorf("unexpected pull request body (-want +got):\n%s", diff)
+	}
+}
+
+func TestPullRequest_ResetStaleBranch(t *testing.T) {
+	fake, server := newFakeGitHub(t, map[string]string{"zones/example.com.yaml": pullRequestZone})
+	fake.commit("octodns/main", map[string]string{"zones/example.com.yaml": "stale:\n  type: A\n  value: 9.9.9.9\n"}, "stale")
+	fake.pulls = []*fakePull{{Number: 1, State: "closed", Head: "octodns/main", Base: "main"}}
+
+	client := newPullRequestTestClient(t, server, &PullRequestOptions{})
+	if err := editRecord(t, client, "new", "2.2.2.2"); err != nil {
+		t.Fatalf("flush failed: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	want := pullRequestZone + "new:\n  - ttl: 300\n    type: A\n    value: 2.2.2.2\n"
+	if got := fake.file("octodns/main", "zones/example.com.yaml"); got != want {
+		t.Errorf("unexpected file content on feature branch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if len(fake.pulls) != 2 || fake.pulls[1].State != "open" {
+		t.Errorf("expected a new pull request next to the closed one, got %d", len(fake.pulls))
+	}
+}
+
+func TestPullRequest_NotSupported(t *testing.T) {
+	client, err := NewLocalClient(t.TempDir(), false, 0)
+	if err != nil {
+		t.Fatalf("NewLocalClient failed: %s", err)
+	}
+	if err = client.SetPullRequest(&PullRequestOptions{}); err == nil {
+		t.Errorf("expected an error for a git provider without pull requests")
+	}
+}
@@ -2,8 +2,11 @@ package models
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"net"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -16,26 +19,6 @@ type Terraform struct {
 	Hash string `yaml:",omitempty"`
 }
 
-type OctodnsRecordConfig struct {
-	Cloudflare *OctodnsCloudflare `yaml:",omitempty"`
-	AzureDNS   *OctodnsAzureDNS   `yaml:",omitempty"`
-}
-
-type OctodnsCloudflare struct {
-	Proxied bool `yaml:",omitempty"`
-	AutoTTL bool `yaml:",omitempty"`
-}
-
-type OctodnsAzureDNS struct {
-	Healthcheck OctodnsAzureDNSHealthcheck `yaml:",omitempty"`
-}
-
-type OctodnsAzureDNSHealthcheck struct {
-	Interval    int `yaml:",omitempty"`
-	Timeout     int `yaml:",omitempty"`
-	NumFailures int `yaml:",omitempty"`
-}
-
 type BaseRecord struct {
 	RecordChild  *yaml.Node `yaml:"-"`
 	RecordNode   *yaml.Node `yaml:"-"`
@@ -47,13 +30,29 @@ type BaseRecord struct {
 	TTL          int
 	Terraform    Terraform
 	Octodns      OctodnsRecordConfig
+	Dynamic      *Dynamic
+	Geo          map[string][]string
+
+	// Ignored, Included and Excluded are the lifecycle flags octoDNS reads
+	// from the octodns config, they are written into it by MarshalYAML.
+	Ignored  bool
+	Included []string
+	Excluded []string
 }
 
 type Record struct {
 	BaseRecord
 	Values []RecordValue `yaml:"values" line_comment:"Enable or disable."`
+
+	// layout holds record keys we don't model, so hand-maintained config
+	// survives an update of the record. geoLayout holds the order of the geo
+	// codes.
+	layout    yamlMapping
+	geoLayout yamlMapping
 }
 
+var recordKeys = []string{"ttl", "type", "value", "values", "terraform", "octodns", "dynamic", "geo"}
+
 type recordYamlUnmarshal struct {
 	Name      string              `yaml:",omitempty"`
 	TTL       int                 `yaml:",omitempty"`
@@ -62,6 +61,8 @@ type recordYamlUnmarshal struct {
 	Values    yaml.Node           `yaml:",omitempty"`
 	Terraform Terraform           `yaml:",omitempty"`
 	Octodns   OctodnsRecordConfig `yaml:",omitempty"`
+	Dynamic   *Dynamic            `yaml:",omitempty"`
+	Geo       yaml.Node           `yaml:",omitempty"`
 }
 
 func (r *Record) UpdateYaml() error {
@@ -99,6 +100,12 @@ func (r *Record) ValuesAsString() []string {
 			ret = append(ret, v.StringNAPTR())
 		case TYPE_LOC.String():
 			ret = append(ret, v.StringLOC())
+		case TYPE_TLSA.String():
+			ret = append(ret, v.StringTLSA())
+		case TYPE_DS.String():
+			ret = append(ret, v.StringDS())
+		case TYPE_SVCB.String(), TYPE_HTTPS.String():
+			ret = append(ret, v.StringSVCB())
 		}
 
 	}
@@ -123,12 +130,18 @@ func (r *Record) AddValueFromString(valueString string) error {
 		err = value.UnmarshalStringA(valueString)
 	case TYPE_AAAA.String():
 		err = value.UnmarshalStringAAAA(valueString)
+	case TYPE_ALIAS.String():
+		err = value.UnmarshalStringFQDN(valueString)
 	case TYPE_CAA.String():
 		err = value.UnmarshalStringCAA(valueString)
 	case TYPE_CNAME.String():
 		err = value.UnmarshalStringFQDN(valueString)
 	case TYPE_DNAME.String():
 		err = value.UnmarshalStringFQDN(valueString)
+	case TYPE_DS.String():
+		err = value.UnmarshalStringDS(valueString)
+	case TYPE_HTTPS.String():
+		err = value.UnmarshalStringSVCB(valueString)
 	case TYPE_LOC.String():
 		err = value.UnmarshalStringLOC(valueString)
 	case TYPE_MX.String():
@@ -137,6 +150,8 @@ func (r *Record) AddValueFromString(valueString string) error {
 		err = value.UnmarshalStringNAPTR(valueString)
 	case TYPE_NS.String():
 		err = value.UnmarshalStringNS(valueString)
+	case TYPE_OPENPGPKEY.String():
+		err = value.UnmarshalStringOPENPGPKEY(valueString)
 	case TYPE_PTR.String():
 		err = value.UnmarshalStringFQDN(valueString)
 	case TYPE_SPF.String():
@@ -145,8 +160,12 @@ func (r *Record) AddValueFromString(valueString string) error {
 		err = value.UnmarshalStringSRV(valueString)
 	case TYPE_SSHFP.String():
 		err = value.UnmarshalStringSSHFP(valueString)
+	case TYPE_SVCB.String():
+		err = value.UnmarshalStringSVCB(valueString)
+	case TYPE_TLSA.String():
+		err = value.UnmarshalStringTLSA(valueString)
 	case TYPE_TXT.String():
-		err = value.UnmarshalString(valueString)
+		err = value.UnmarshalStringTXT(valueString)
 	case TYPE_URLFWD.String():
 		err = value.UnmarshalStringURLFWD(valueString)
 	}
@@ -193,6 +212,19 @@ func (r *Record) UnmarshalYAML(
 	r.Terraform = raw.Terraform
 	r.Type = raw.Type
 	r.Octodns = raw.Octodns
+	r.Dynamic = raw.Dynamic
+	r.layout = readMapping(value, recordKeys)
+
+	if err := r.readLifecycle(); err != nil {
+		return err
+	}
+
+	if !raw.Geo.IsZero() {
+		if err := raw.Geo.Decode(&r.Geo); err != nil {
+			return err
+		}
+		r.geoLayout = readOrder(&raw.Geo)
+	}
 
 	if !raw.Value.IsZero() {
 		rvValue := RecordValue{}
@@ -219,14 +251,17 @@ func (r Record) MarshalYAML() (interface{}, error) {
 		Type:      r.Type,
 		TTL:       r.TTL,
 		Terraform: r.Terraform,
-		Octodns:   r.Octodns,
+		Dynamic:   r.Dynamic,
 	}
 	node := yaml.Node{}
 	var err error
+	if out.Octodns, err = r.lifecycleOctodns(); err != nil {
+		return nil, err
+	}
 	if len(r.Values) == 0 {
 		return nil, fmt.Errorf("0 Values encountered: %s, %s ", r.Name, r.Type)
 	}
-	if len(r.Values) > 1 {
+	if len(r.Values) > 1 || r.layout.has("values") {
 		err = node.Encode(r.Values)
 		out.Values = node
 	} else {
@@ -237,7 +272,15 @@ func (r Record) MarshalYAML() (interface{}, error) {
 		return nil, err
 	}
 
-	return out, nil
+	if len(r.Geo) > 0 {
+		geo, err := r.geoLayout.encode(r.Geo)
+		if err != nil {
+			return nil, err
+		}
+		out.Geo = *geo
+	}
+
+	return r.layout.encode(out)
 
 }
 
@@ -296,6 +339,25 @@ type baseRecordValue struct {
 	Path    *string `yaml:",omitempty"`
 	Query   *int    `yaml:",omitempty"`
 	//Target  string `yaml:",omitempty"`
+
+	// TLSA
+
+	CertificateAssociationData *string `yaml:"certificate_association_data,omitempty"`
+	CertificateUsage           *int    `yaml:"certificate_usage,omitempty"`
+	MatchingType               *int    `yaml:"matching_type,omitempty"`
+	Selector                   *int    `yaml:",omitempty"`
+
+	// DS
+	//Algorithm int `yaml:",omitempty"`
+	Digest     *string `yaml:",omitempty"`
+	DigestType *int    `yaml:"digest_type,omitempty"`
+	KeyTag     *int    `yaml:"key_tag,omitempty"`
+
+	// SVCB, HTTPS
+
+	SvcParams   map[string]interface{} `yaml:"svcparams,omitempty"`
+	SvcPriority *int                   `yaml:"svcpriority,omitempty"`
+	TargetName  *string                `yaml:"targetname,omitempty"`
 }
 
 type RecordValue struct {
@@ -460,6 +522,28 @@ func (r *RecordValue) StringLOC() string {
 
 }
 
+func (r *RecordValue) StringTLSA() string {
+	if r.CertificateUsage != nil && r.Selector != nil && r.MatchingType != nil && r.CertificateAssociationData != nil {
+		return fmt.Sprintf(
+			"%d %d %d %s",
+			*r.CertificateUsage, *r.Selector, *r.MatchingType, *r.CertificateAssociationData,
+		)
+	} else {
+		return ""
+	}
+}
+
+func (r *RecordValue) StringDS() string {
+	if r.KeyTag != nil && r.Algorithm != nil && r.DigestType != nil && r.Digest != nil {
+		return fmt.Sprintf(
+			"%d %d %d %s",
+			*r.KeyTag, *r.Algorithm, *r.DigestType, *r.Digest,
+		)
+	} else {
+		return ""
+	}
+}
+
 func (r *RecordValue) validateIP(ip string) error {
 	parsed := net.ParseIP(ip)
 	if parsed == nil {
@@ -605,7 +689,7 @@ func (r *RecordValue) UnmarshalStringSRV(value string) error {
 
 }
 
-var regStringCAA = regexp.MustCompile(`^(?P<flags>\d+) (?P<tag>issue|issuewild|iodef) (?P<target>(?P<host>.+)(?P<policy>|; policy=(dv|ev|cv)))$`)
+var regStringCAA = regexp.MustCompile(`^(?P<flags>\d+) (?P<tag>[^ ]+) (?P<value>.+)$`)
 
 func (r *RecordValue) UnmarshalStringCAA(value string) error {
 
@@ -614,27 +698,61 @@ func (r *RecordValue) UnmarshalStringCAA(value string) error {
 		return err
 	}
 
-	intFlags, err := strconv.Atoi(parts["flags"])
-	if err != nil || intFlags < 0 || intFlags > 128 {
-		return fmt.Errorf("flag part should be an int between 0 and 128")
-	}
+	r.Flags = RefString(parts["flags"])
+	r.Tag = RefString(parts["tag"])
+	r.Value = RefString(parts["value"])
+
+	return r.validateCAA()
+
+}
 
-	if parts["tag"] != "iodef" && r.validateFQDN(parts["host"], false) != nil {
-		return fmt.Errorf("issuer should be a valid hostname")
+var (
+	regCAATag       = regexp.MustCompile(`^[a-zA-Z0-9]{1,15}$`)
+	regCAAIssuer    = regexp.MustCompile(`^[a-zA-Z0-9](-*[a-zA-Z0-9])*(\.[a-zA-Z0-9](-*[a-zA-Z0-9])*)*$`)
+	regCAAParameter = regexp.MustCompile(`^[a-zA-Z0-9](-*[a-zA-Z0-9])*[ \t]*=[ \t]*[\x21-\x3a\x3c-\x7e]*$`)
+)
+
+// validateCAA checks a CAA value as described in RFC 8659. Values of the
+// issue tags and iodef are checked, values of other tags are free form.
+func (r *RecordValue) validateCAA() error {
+	if *r.Flags != "0" && *r.Flags != "128" {
+		return fmt.Errorf("flags should be 0, or 128 for issuer critical")
 	}
-	if parts["tag"] == "iodef" && !strings.Contains(parts["host"], "mailto:") {
-		return fmt.Errorf("issuer should be a valid mailto link")
+	if !regCAATag.MatchString(*r.Tag) {
+		return fmt.Errorf("tag should be 1 to 15 letters and digits")
 	}
 
-	r.Tag = RefString(parts["tag"])
-	r.Flags = RefString(parts["flags"])
-	r.Value = RefString(parts["target"])
-
+	switch strings.ToLower(*r.Tag) {
+	case "issue", "issuewild", "issuevmc", "issuemail":
+		issuer, parameters, _ := strings.Cut(*r.Value, ";")
+		issuer = strings.Trim(issuer, " \t")
+		if issuer != "" && !regCAAIssuer.MatchString(issuer) {
+			return fmt.Errorf("issuer should be a domain name without trailing dot, or empty to forbid issuance")
+		}
+		parameters = strings.Trim(parameters, " \t")
+		if parameters == "" {
+			return nil
+		}
+		for _, parameter := range strings.Split(parameters, ";") {
+			if !regCAAParameter.MatchString(strings.Trim(parameter, " \t")) {
+				return fmt.Errorf("parameter %q should be tag=value", parameter)
+			}
+		}
+	case "iodef":
+		target, err := url.Parse(*r.Value)
+		switch {
+		case err != nil:
+		case target.Scheme == "mailto" && strings.Contains(target.Opaque, "@"):
+			return nil
+		case (target.Scheme == "http" || target.Scheme == "https") && target.Host != "":
+			return nil
+		}
+		return fmt.Errorf("iodef should be a mailto:, http:// or https:// url")
+	}
 	return nil
-
 }
 
-var regStringURLFWD = regexp.MustCompile(`^(?P<code>0|301|302) (?P<masking>0|1|2) (?P<path>[^ ]+) (?P<query>0|1) (?P<target>.+)$`)
+var regStringURLFWD = regexp.MustCompile(`^(?P<code>\d+) (?P<masking>\d+) (?P<path>[^ ]+) (?P<query>\d+) (?P<target>.+)$`)
 
 func (r *RecordValue) UnmarshalStringURLFWD(value string) error {
 
@@ -643,21 +761,41 @@ func (r *RecordValue) UnmarshalStringURLFWD(value string) error {
 		return err
 	}
 
-	if strings.HasSuffix(parts["path"], "/") && parts["path"] != "/" {
-		return fmt.Errorf("path must not end with a slash (/)")
-	}
-
-	//@todo: Validate values
 	r.Code = RefStringAsInt(parts["code"])
 	r.Masking = RefStringAsInt(parts["masking"])
 	r.Path = RefString(parts["path"])
 	r.Query = RefStringAsInt(parts["query"])
 	r.Target = RefString(parts["target"])
 
-	return nil
+	return r.validateURLFWD()
 
 }
 
+// validateURLFWD checks the parts of an URLFWD value, the target has to be
+// an absolute http(s) url.
+func (r *RecordValue) validateURLFWD() error {
+	if r.Code == nil || (*r.Code != 0 && *r.Code != 301 && *r.Code != 302) {
+		return fmt.Errorf("code should be 0, 301 or 302")
+	}
+	if r.Masking == nil || *r.Masking < 0 || *r.Masking > 2 {
+		return fmt.Errorf("masking should be 0, 1 or 2")
+	}
+	if r.Query == nil || (*r.Query != 0 && *r.Query != 1) {
+		return fmt.Errorf("query should be 0 or 1")
+	}
+	if !strings.HasPrefix(*r.Path, "/") {
+		return fmt.Errorf("path should start with a slash (/)")
+	}
+	if strings.HasSuffix(*r.Path, "/") && *r.Path != "/" {
+		return fmt.Errorf("path must not end with a slash (/)")
+	}
+	target, err := url.Parse(*r.Target)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" || strings.Contains(*r.Target, " ") {
+		return fmt.Errorf("target should be an http or https url, eq: https://www.example.com/path")
+	}
+	return nil
+}
+
 var regStringSSHFP = regexp.MustCompile(`^(?P<algorithm>\d+) (?P<fingerprinttype>\d+) (?P<fingerprint>.+)$`)
 
 func (r *RecordValue) UnmarshalStringSSHFP(value string) error {
@@ -667,15 +805,31 @@ func (r *RecordValue) UnmarshalStringSSHFP(value string) error {
 		return err
 	}
 
-	//@todo: Validate values
 	r.Algorithm = RefStringAsInt(parts["algorithm"])
 	r.FingerprintType = RefStringAsInt(parts["fingerprinttype"])
 	r.Fingerprint = RefString(parts["fingerprint"])
 
-	return nil
+	return r.validateSSHFP()
 
 }
 
+// validateSSHFP checks the parts of an SSHFP value, the length of the
+// fingerprint has to match the fingerprint type.
+func (r *RecordValue) validateSSHFP() error {
+	if r.Algorithm == nil || *r.Algorithm < 1 || *r.Algorithm > 4 {
+		return fmt.Errorf("algorithm should be 1 (RSA), 2 (DSA), 3 (ECDSA) or 4 (Ed25519)")
+	}
+	lengths := map[int]int{1: 40, 2: 64}
+	if r.FingerprintType == nil {
+		return fmt.Errorf("fingerprint_type should be 1 (SHA-1) or 2 (SHA-256)")
+	}
+	length, ok := lengths[*r.FingerprintType]
+	if !ok {
+		return fmt.Errorf("fingerprint_type should be 1 (SHA-1) or 2 (SHA-256)")
+	}
+	return validateHex("fingerprint", *r.Fingerprint, length)
+}
+
 var regStringNAPTR = regexp.MustCompile(`^(?P<order>\d+) (?P<preference>\d+) \"(?P<flags>.+)\" \"(?P<service>.+)\" \"(?P<regexp>.+)\" (?P<replacement>.+)$`)
 
 func (r *RecordValue) UnmarshalStringNAPTR(value string) error {
@@ -685,7 +839,6 @@ func (r *RecordValue) UnmarshalStringNAPTR(value string) error {
 		return err
 	}
 
-	//@todo: Validate values
 	r.Order = RefStringAsInt(parts["order"])
 	r.Preference = RefStringAsInt(parts["preference"])
 	r.Flags = RefString(parts["flags"])
@@ -693,12 +846,31 @@ func (r *RecordValue) UnmarshalStringNAPTR(value string) error {
 	r.Regexp = RefString(parts["regexp"])
 	r.Replacement = RefString(parts["replacement"])
 
+	return r.validateNAPTR()
+}
+
+// validateNAPTR checks the parts of a NAPTR value.
+func (r *RecordValue) validateNAPTR() error {
+	if r.Order == nil || *r.Order < 0 || *r.Order > 65535 {
+		return fmt.Errorf("order should be between 0 and 65535")
+	}
+	if r.Preference == nil || *r.Preference < 0 || *r.Preference > 65535 {
+		return fmt.Errorf("preference should be between 0 and 65535")
+	}
+	switch *r.Flags {
+	case "S", "A", "U", "P":
+	default:
+		return fmt.Errorf("flags should be S, A, U or P")
+	}
+	if *r.Replacement != "." && r.validateFQDN(*r.Replacement, true) != nil {
+		return fmt.Errorf("replacement should be . or a fqdn ending with a dot")
+	}
 	return nil
 }
 
 var regStringLOC = regexp.MustCompile(`^(?P<latdeg>\d+) (|(?P<latm>\d+) (|(?P<lats>\d+(|.\d+)) ))(?P<latdir>N|S) ` +
 	`(?P<longdeg>\d+) (|(?P<longm>\d+) (|(?P<longs>\d+(|.\d+)) ))(?P<longdir>E|W) ` +
-	`(?P<alt>\d+(|.\d+))` +
+	`(?P<alt>-?\d+(|.\d+))` +
 	`(| (?P<size>\d+)(| (?P<prh>\d+)(| (?P<prv>\d+))))$`)
 
 func (r *RecordValue) UnmarshalStringLOC(value string) error {
@@ -707,7 +879,6 @@ func (r *RecordValue) UnmarshalStringLOC(value string) error {
 		return err
 	}
 
-	//@todo: Validate values
 	r.LatDegrees = RefStringAsInt(parts["latdeg"])
 	r.LatMinutes = RefStringAsInt(parts["latm"])
 	r.LatSeconds = RefStringAsFloat64(parts["lats"])
@@ -723,8 +894,59 @@ func (r *RecordValue) UnmarshalStringLOC(value string) error {
 	r.PrecisionHorz = RefStringAsInt(parts["prh"])
 	r.PrecisionVert = RefStringAsInt(parts["prv"])
 
+	// optional parts are nil when left out, but also when they don't fit
+	// an int
+	for _, part := range [][2]string{{"latm", "lat_minutes"}, {"longm", "long_minutes"}, {"size", "size"}, {"prh", "precision_horz"}, {"prv", "precision_vert"}} {
+		if parts[part[0]] != "" && RefStringAsInt(parts[part[0]]) == nil {
+			return fmt.Errorf("%s is out of range", part[1])
+		}
+	}
+
+	return r.validateLOC()
+
+}
+
+// validateLOC checks the parts of a LOC value against the limits of RFC 1876.
+func (r *RecordValue) validateLOC() error {
+	if *r.LatDirection != "N" && *r.LatDirection != "S" {
+		return fmt.Errorf("lat_direction should be N or S")
+	}
+	if *r.LongDirection != "E" && *r.LongDirection != "W" {
+		return fmt.Errorf("long_direction should be E or W")
+	}
+	if err := validateLOCAngle("lat", 90, r.LatDegrees, r.LatMinutes, r.LatSeconds); err != nil {
+		return err
+	}
+	if err := validateLOCAngle("long", 180, r.LongDegrees, r.LongMinutes, r.LongSeconds); err != nil {
+		return err
+	}
+	if *r.Altitude < -100000 || *r.Altitude > 42849672.95 {
+		return fmt.Errorf("altitude should be between -100000.00 and 42849672.95")
+	}
+	for name, meters := range map[string]*int{"size": r.Size, "precision_horz": r.PrecisionHorz, "precision_vert": r.PrecisionVert} {
+		if meters != nil && (*meters < 0 || *meters > 90000000) {
+			return fmt.Errorf("%s should be between 0 and 90000000", name)
+		}
+	}
 	return nil
+}
 
+// validateLOCAngle checks the degrees, minutes and seconds of a latitude or
+// longitude, at the maximum degrees the minutes and seconds have to be 0.
+func validateLOCAngle(prefix string, maxDegrees int, degrees, minutes *int, seconds *float64) error {
+	if degrees == nil || *degrees < 0 || *degrees > maxDegrees {
+		return fmt.Errorf("%s_degrees should be between 0 and %d", prefix, maxDegrees)
+	}
+	if minutes != nil && (*minutes < 0 || *minutes > 59) {
+		return fmt.Errorf("%s_minutes should be between 0 and 59", prefix)
+	}
+	if seconds != nil && (*seconds < 0 || *seconds >= 60) {
+		return fmt.Errorf("%s_seconds should be between 0 and 59.999", prefix)
+	}
+	if *degrees == maxDegrees && (minutes != nil && *minutes != 0 || seconds != nil && *seconds != 0) {
+		return fmt.Errorf("%s_minutes and %s_seconds should be 0 at %d degrees", prefix, prefix, maxDegrees)
+	}
+	return nil
 }
 func (r *RecordValue) UnmarshalStringNS(value string) error {
 
@@ -740,17 +962,96 @@ func (r *RecordValue) UnmarshalStringNS(value string) error {
 
 func (r *RecordValue) UnmarshalStringSPF(value string) error {
 
-	if !strings.HasPrefix(value, "v=spf1") {
-		return fmt.Errorf("value should start with v=spf1")
+	if err := validateSPF(unchunk(value)); err != nil {
+		return err
 	}
 
-	reg, err := regexp.Compile(`((-|~|\+)all)`)
+	return r.UnmarshalStringTXT(value)
+}
+
+var regStringTLSA = regexp.MustCompile(`^(?P<usage>\d+) (?P<selector>\d+) (?P<matchingtype>\d+) (?P<data>[^ ]+)$`)
+
+func (r *RecordValue) UnmarshalStringTLSA(value string) error {
+	parts, err := regexToMap(value, regStringTLSA)
 	if err != nil {
 		return err
 	}
 
-	if !reg.MatchString(value) {
-		return fmt.Errorf("value should contain a part for ALL")
+	r.CertificateUsage = RefStringAsInt(parts["usage"])
+	r.Selector = RefStringAsInt(parts["selector"])
+	r.MatchingType = RefStringAsInt(parts["matchingtype"])
+	r.CertificateAssociationData = RefString(parts["data"])
+
+	return r.validateTLSA()
+}
+
+// validateTLSA checks the parts of a TLSA value, the length of the
+// association data has to match the hash of the matching type.
+func (r *RecordValue) validateTLSA() error {
+	if r.CertificateUsage == nil || *r.CertificateUsage < 0 || *r.CertificateUsage > 3 {
+		return fmt.Errorf("certificate_usage should be 0, 1, 2 or 3")
+	}
+	if r.Selector == nil || *r.Selector < 0 || *r.Selector > 1 {
+		return fmt.Errorf("selector should be 0 or 1")
+	}
+	if r.MatchingType == nil || *r.MatchingType < 0 || *r.MatchingType > 2 {
+		return fmt.Errorf("matching_type should be 0, 1 or 2")
+	}
+	lengths := map[int]int{1: 64, 2: 128}
+	return validateHex("certificate_association_data", *r.CertificateAssociationData, lengths[*r.MatchingType])
+}
+
+var regStringDS = regexp.MustCompile(`^(?P<keytag>\d+) (?P<algorithm>\d+) (?P<digesttype>\d+) (?P<digest>[^ ]+)$`)
+
+func (r *RecordValue) UnmarshalStringDS(value string) error {
+	parts, err := regexToMap(value, regStringDS)
+	if err != nil {
+		return err
+	}
+
+	r.KeyTag = RefStringAsInt(parts["keytag"])
+	r.Algorithm = RefStringAsInt(parts["algorithm"])
+	r.DigestType = RefStringAsInt(parts["digesttype"])
+	r.Digest = RefString(parts["digest"])
+
+	return r.validateDS()
+}
+
+// validateDS checks the parts of a DS value, the length of the digest has to
+// match the digest type.
+func (r *RecordValue) validateDS() error {
+	if r.KeyTag == nil || *r.KeyTag < 0 || *r.KeyTag > 65535 {
+		return fmt.Errorf("key_tag should be between 0 and 65535")
+	}
+	if r.Algorithm == nil || *r.Algorithm < 1 || *r.Algorithm > 255 {
+		return fmt.Errorf("algorithm should be between 1 and 255")
+	}
+	lengths := map[int]int{1: 40, 2: 64, 3: 64, 4: 96}
+	if r.DigestType == nil {
+		return fmt.Errorf("digest_type should be 1 (SHA-1), 2 (SHA-256), 3 (GOST) or 4 (SHA-384)")
+	}
+	length, ok := lengths[*r.DigestType]
+	if !ok {
+		return fmt.Errorf("digest_type should be 1 (SHA-1), 2 (SHA-256), 3 (GOST) or 4 (SHA-384)")
+	}
+	return validateHex("digest", *r.Digest, length)
+}
+
+// validateHex checks the value is hex encoded, with the given number of
+// characters unless length is 0.
+func validateHex(name, value string, length int) error {
+	if _, err := hex.DecodeString(value); err != nil || value == "" {
+		return fmt.Errorf("%s should be hex encoded", name)
+	}
+	if length > 0 && len(value) != length {
+		return fmt.Errorf("%s should be %d hex characters, got %d", name, length, len(value))
+	}
+	return nil
+}
+
+func (r *RecordValue) UnmarshalStringOPENPGPKEY(value string) error {
+	if _, err := base64.StdEncoding.DecodeString(value); err != nil || value == "" {
+		return fmt.Errorf("value should be a base64 encoded public key")
 	}
 
 	return r.UnmarshalString(value)
@@ -188,6 +188,31 @@ func validateWriteComplexValues(t *testing.T, name string, rType RType, wantsVal
 
 }
 
+func validateReadComplexValues(t *testing.T, name string, rType RType, wantsValues []baseRecordValue, wantStrValues []string) {
+	rt, err := getType(name, rType)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	check := TypesChecked[rType.String()]
+	check.Read()
+
+	checkAmountOfValues(t, rt, len(wantsValues))
+	if t.Failed() {
+		return
+	}
+
+	for i, w := range wantsValues {
+		v := rt.Values[i]
+		var r DiffReporter
+		cmp.Equal(v.baseRecordValue, w, cmp.Reporter(&r))
+		if len(r.diffs) > 0 {
+			t.Errorf("%s value %d mismatch (-want +got):\n%s", rt.Type, i, r.String())
+		}
+	}
+	validateStringValues(t, rt, wantStrValues)
+}
+
 func refFloat64(value float64) *float64 {
 	return &value
 }
@@ -387,6 +412,44 @@ func TestRecord_Write_TXT(t *testing.T) {
 
 }
 
+// TestRecord_Read_ALIAS get an ALIAS record from unit.tests, checking
+// for a valid return value.
+func TestRecord_Read_ALIAS(t *testing.T) {
+	validateReadSimpleValues(t, "", TYPE_ALIAS, []string{"www." + fqdn})
+}
+
+// TestRecord_Write_ALIAS Create an ALIAS record, checking
+// for a valid return value.
+func TestRecord_Write_ALIAS(t *testing.T) {
+	var err error
+	_, err = validateWriteStringValues(t, "", TYPE_ALIAS, []string{fqdn})
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+
+	wrongValues := []string{fqdnNoDot, ipv4, ipv6}
+	_, _ = validateWriteWrongStringValues(t, "", TYPE_ALIAS, wrongValues)
+}
+
+// TestRecord_Read_OPENPGPKEY get an OPENPGPKEY record from unit.tests, checking
+// for a valid return value.
+func TestRecord_Read_OPENPGPKEY(t *testing.T) {
+	validateReadSimpleValues(t, "openpgpkey", TYPE_OPENPGPKEY, []string{"mDMEZQ1aGxYJKwYBBAHaRw8BAQdA"})
+}
+
+// TestRecord_Write_OPENPGPKEY Create an OPENPGPKEY record, checking
+// for a valid return value.
+func TestRecord_Write_OPENPGPKEY(t *testing.T) {
+	var err error
+	_, err = validateWriteStringValues(t, TYPE_OPENPGPKEY.LowerString(), TYPE_OPENPGPKEY, []string{"mDMEZQ1aGxYJKwYBBAHaRw8BAQdA"})
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+
+	wrongValues := []string{"", randomTxt, "mDMEZQ1aGxYJKwYBBAHaRw8BAQd"}
+	_, _ = validateWriteWrongStringValues(t, TYPE_OPENPGPKEY.LowerString(), TYPE_OPENPGPKEY, wrongValues)
+}
+
 /**** Complex Record types ****/
 
 // TestRecord_Read_MX get an MX record from unit.tests, checking
@@ -548,6 +611,36 @@ func TestRecord_Write_CAA(t *testing.T) {
 			Tag:   refString("iodef"),
 			Value: refString("mailto:ca@" + fqdnNoDot),
 		},
+		{
+			Flags: refString("128"),
+			Tag:   refString("issue"),
+			Value: refString("letsencrypt.org; validationmethods=dns-01; accounturi=https://acme-v02.api.letsencrypt.org/acme/acct/1234"),
+		},
+		{
+			Flags: refString("0"),
+			Tag:   refString("issuewild"),
+			Value: refString(";"),
+		},
+		{
+			Flags: refString("0"),
+			Tag:   refString("issuevmc"),
+			Value: refString("digicert.com"),
+		},
+		{
+			Flags: refString("0"),
+			Tag:   refString("issuemail"),
+			Value: refString("ca." + fqdnNoDot + ";"),
+		},
+		{
+			Flags: refString("0"),
+			Tag:   refString("iodef"),
+			Value: refString("https://iodef." + fqdnNoDot + "/report"),
+		},
+		{
+			Flags: refString("128"),
+			Tag:   refString("tbs"),
+			Value: refString("Unknown tags take any value"),
+		},
 	}
 	wantStrValues := []string{}
 	for _, w := range wants {
@@ -559,6 +652,22 @@ func TestRecord_Write_CAA(t *testing.T) {
 
 	_, _ = validateWriteComplexValues(t, TYPE_CAA.LowerString(), TYPE_CAA, wants, wantStrValues)
 
+	wrongValues := []string{
+		"1 issue ca." + fqdnNoDot,
+		"256 issue ca." + fqdnNoDot,
+		"0 is-sue ca." + fqdnNoDot,
+		"0 averylongtagname ca." + fqdnNoDot,
+		"0 issue ca." + fqdn,
+		"0 issue ca_" + fqdnNoDot,
+		"0 issue ca." + fqdnNoDot + "; policy",
+		"0 issue ca." + fqdnNoDot + "; policy=a b",
+		"0 iodef ca@" + fqdnNoDot,
+		"0 iodef ftp://" + fqdnNoDot,
+		"0 iodef https://",
+		"0 issue",
+	}
+	_, _ = validateWriteWrongStringValues(t, TYPE_CAA.LowerString(), TYPE_CAA, wrongValues)
+
 }
 
 // TestRecord_Read_SRV get an SRV record from unit.tests, checking
@@ -1021,6 +1130,176 @@ func TestRecord_Write_LOC(t *testing.T) {
 
 }
 
+var (
+	tlsaWants = []baseRecordValue{
+		{
+			CertificateAssociationData: refString("385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1"),
+			CertificateUsage:           refInt(3), // DANE-EE
+			MatchingType:               refInt(1), // SHA-256
+			Selector:                   refInt(1), // public key
+		},
+	}
+	tlsaStrValues = []string{"3 1 1 385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1"}
+
+	dsWants = []baseRecordValue{
+		{
+			Algorithm:  refInt(5),
+			Digest:     refString("2bb183af5f22588179a53b0a98631fad1a292118"),
+			DigestType: refInt(1), // SHA-1
+			KeyTag:     refInt(60485),
+		},
+		{
+			Algorithm:  refInt(13),
+			Digest:     refString("385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1"),
+			DigestType: refInt(2), // SHA-256
+			KeyTag:     refInt(2371),
+		},
+	}
+	dsStrValues = []string{
+		"60485 5 1 2bb183af5f22588179a53b0a98631fad1a292118",
+		"2371 13 2 385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1",
+	}
+
+	svcbWants = []baseRecordValue{
+		{
+			SvcParams: map[string]interface{}{
+				"alpn":      []interface{}{"h2"},
+				"ipv6hint":  []interface{}{"2001:db8::1"},
+				"mandatory": []interface{}{"alpn"},
+			},
+			SvcPriority: refInt(1),
+			TargetName:  refString("svc." + fqdn),
+		},
+		{
+			SvcParams: map[string]interface{}{
+				"key65333": "foo",
+				"port":     8443,
+			},
+			SvcPriority: refInt(2),
+			TargetName:  refString("."),
+		},
+	}
+	svcbStrValues = []string{
+		"1 svc." + fqdn + " mandatory=alpn alpn=h2 ipv6hint=2001:db8::1",
+		"2 . port=8443 key65333=foo",
+	}
+
+	httpsWants = []baseRecordValue{
+		{
+			SvcParams: map[string]interface{}{
+				"alpn": []interface{}{"h2", "h3"},
+				"port": 443,
+			},
+			SvcPriority: refInt(1),
+			TargetName:  refString("."),
+		},
+		{
+			SvcParams: map[string]interface{}{
+				"alpn":            []interface{}{"h3"},
+				"ipv4hint":        []interface{}{"192.0.2.1", "192.0.2.2"},
+				"no-default-alpn": nil,
+			},
+			SvcPriority: refInt(2),
+			TargetName:  refString("cdn." + fqdn),
+		},
+	}
+	httpsStrValues = []string{
+		"1 . alpn=h2,h3 port=443",
+		"2 cdn." + fqdn + " alpn=h3 no-default-alpn ipv4hint=192.0.2.1,192.0.2.2",
+	}
+)
+
+// TestRecord_Read_TLSA get a TLSA record from unit.tests, checking
+// for a valid return value.
+func TestRecord_Read_TLSA(t *testing.T) {
+	validateReadComplexValues(t, "tlsa", TYPE_TLSA, tlsaWants, tlsaStrValues)
+}
+
+// TestRecord_Write_TLSA Create a TLSA record, checking
+// for a valid return value.
+func TestRecord_Write_TLSA(t *testing.T) {
+	_, _ = validateWriteComplexValues(t, TYPE_TLSA.LowerString(), TYPE_TLSA, tlsaWants, tlsaStrValues)
+
+	wrongValues := []string{
+		"4 1 1 385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1",
+		"3 2 1 385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1",
+		"3 1 3 385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1",
+		"3 1 2 385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1",
+		"3 1 0 not-hex",
+		"3 1 1",
+	}
+	_, _ = validateWriteWrongStringValues(t, TYPE_TLSA.LowerString(), TYPE_TLSA, wrongValues)
+}
+
+// TestRecord_Read_DS get a DS record from unit.tests, checking
+// for a valid return value.
+func TestRecord_Read_DS(t *testing.T) {
+	validateReadComplexValues(t, "ds", TYPE_DS, dsWants, dsStrValues)
+}
+
+// TestRecord_Write_DS Create a DS record, checking
+// for a valid return value.
+func TestRecord_Write_DS(t *testing.T) {
+	_, _ = validateWriteComplexValues(t, TYPE_DS.LowerString(), TYPE_DS, dsWants, dsStrValues)
+
+	wrongValues := []string{
+		"65536 5 1 2bb183af5f22588179a53b0a98631fad1a292118",
+		"60485 0 1 2bb183af5f22588179a53b0a98631fad1a292118",
+		"60485 5 5 2bb183af5f22588179a53b0a98631fad1a292118",
+		"60485 5 2 2bb183af5f22588179a53b0a98631fad1a292118",
+		"60485 5 1 2bb183af5f22588179a53b0a98631fad1a29211z",
+	}
+	_, _ = validateWriteWrongStringValues(t, TYPE_DS.LowerString(), TYPE_DS, wrongValues)
+}
+
+// TestRecord_Read_SVCB get an SVCB record from unit.tests, checking
+// for a valid return value.
+func TestRecord_Read_SVCB(t *testing.T) {
+	validateReadComplexValues(t, "svcb", TYPE_SVCB, svcbWants, svcbStrValues)
+}
+
+// TestRecord_Write_SVCB Create an SVCB record, checking
+// for a valid return value.
+func TestRecord_Write_SVCB(t *testing.T) {
+	_, _ = validateWriteComplexValues(t, TYPE_SVCB.LowerString(), TYPE_SVCB, svcbWants, svcbStrValues)
+
+	wrongValues := []string{
+		"0 svc." + fqdn + " alpn=h2",
+		"1 svc." + fqdnNoDot,
+		"65536 .",
+		"1 . port=443 alpn=h2",
+		"1 . alpn=h2 alpn=h3",
+		"1 . alpn",
+		"1 . port=https",
+		"1 . port=65536",
+		"1 . no-default-alpn",
+		"1 . alpn=h2 no-default-alpn=1",
+		"1 . ipv4hint=" + ipv6,
+		"1 . ipv6hint=" + ipv4,
+		"1 . mandatory=port alpn=h2",
+		"1 . foo=bar",
+		"1 . key1=h2",
+	}
+	_, _ = validateWriteWrongStringValues(t, TYPE_SVCB.LowerString(), TYPE_SVCB, wrongValues)
+}
+
+// TestRecord_Read_HTTPS get an HTTPS record from unit.tests, checking
+// for a valid return value.
+func TestRecord_Read_HTTPS(t *testing.T) {
+	validateReadComplexValues(t, "https", TYPE_HTTPS, httpsWants, httpsStrValues)
+}
+
+// TestRecord_Write_HTTPS Create an HTTPS record, checking
+// for a valid return value.
+func TestRecord_Write_HTTPS(t *testing.T) {
+	_, _ = validateWriteComplexValues(t, TYPE_HTTPS.LowerString(), TYPE_HTTPS, httpsWants, httpsStrValues)
+
+	_, err := validateWriteStringValues(t, TYPE_HTTPS.LowerString(), TYPE_HTTPS, []string{"0 cdn." + fqdn})
+	if err != nil {
+		t.Errorf("Error: %s", err.Error())
+	}
+}
+
 func TestValidateValueString(t *testing.T) {
 
 	cases := []struct {
@@ -1049,6 +1328,50 @@ func TestValidateValueString(t *testing.T) {
 
 		{"SSHFP valid", TYPE_SSHFP.String(), "1 1 bf6b6825d2977c511a475bbefb88aad54a92ac73", false},
 		{"SSHFP too few fields", TYPE_SSHFP.String(), "1 1", true},
+		{"SSHFP SHA-256", TYPE_SSHFP.String(), "4 2 385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1", false},
+		{"SSHFP algorithm out of range", TYPE_SSHFP.String(), "5 1 bf6b6825d2977c511a475bbefb88aad54a92ac73", true},
+		{"SSHFP fingerprint type out of range", TYPE_SSHFP.String(), "1 3 bf6b6825d2977c511a475bbefb88aad54a92ac73", true},
+		{"SSHFP fingerprint length of other type", TYPE_SSHFP.String(), "1 2 bf6b6825d2977c511a475bbefb88aad54a92ac73", true},
+		{"SSHFP fingerprint not hex", TYPE_SSHFP.String(), "1 1 zf6b6825d2977c511a475bbefb88aad54a92ac73", true},
+
+		{"NAPTR valid", TYPE_NAPTR.String(), `100 10 "S" "SIP+D2U" "!^.*$!sip:info@unit.tests!" _sip._udp.unit.tests.`, false},
+		{"NAPTR unknown flags", TYPE_NAPTR.String(), `100 10 "X" "SIP+D2U" "!^.*$!sip:info@unit.tests!" .`, true},
+		{"NAPTR replacement without dot", TYPE_NAPTR.String(), `100 10 "S" "SIP+D2U" "!^.*$!sip:info@unit.tests!" _sip._udp.unit.tests`, true},
+		{"NAPTR order out of range", TYPE_NAPTR.String(), `65536 10 "S" "SIP+D2U" "!^.*$!sip:info@unit.tests!" .`, true},
+
+		{"LOC valid", TYPE_LOC.String(), "90 N 180 W 20.00", false},
+		{"LOC lat degrees out of range", TYPE_LOC.String(), "91 N 115 E 20.00", true},
+		{"LOC long degrees out of range", TYPE_LOC.String(), "31 S 181 E 20.00", true},
+		{"LOC minutes out of range", TYPE_LOC.String(), "31 60 S 115 E 20.00", true},
+		{"LOC seconds out of range", TYPE_LOC.String(), "31 58 60.00 S 115 E 20.00", true},
+		{"LOC minutes at max degrees", TYPE_LOC.String(), "90 1 N 115 E 20.00", true},
+		{"LOC size out of range", TYPE_LOC.String(), "31 S 115 E 20.00 90000001", true},
+		{"LOC negative altitude", TYPE_LOC.String(), "31 S 115 E -20.50", false},
+		{"LOC altitude out of range", TYPE_LOC.String(), "31 S 115 E -100000.01", true},
+
+		{"URLFWD valid", TYPE_URLFWD.String(), "301 2 /path 1 https://www.unit.tests/target?q=1", false},
+		{"URLFWD target without scheme", TYPE_URLFWD.String(), "301 2 / 0 www.unit.tests", true},
+		{"URLFWD target other scheme", TYPE_URLFWD.String(), "301 2 / 0 ftp://www.unit.tests", true},
+		{"URLFWD target without host", TYPE_URLFWD.String(), "301 2 / 0 http:///path", true},
+		{"URLFWD path without slash", TYPE_URLFWD.String(), "301 2 path 0 http://www.unit.tests", true},
+		{"URLFWD code out of range", TYPE_URLFWD.String(), "303 2 / 0 http://www.unit.tests", true},
+
+		{"CAA critical with parameters", TYPE_CAA.String(), "128 issue letsencrypt.org; validationmethods=dns-01", false},
+		{"CAA forbid issuance", TYPE_CAA.String(), "0 issue ;", false},
+		{"CAA reserved flags", TYPE_CAA.String(), "64 issue letsencrypt.org", true},
+		{"CAA iodef https", TYPE_CAA.String(), "0 iodef https://unit.tests/iodef", false},
+
+		{"TLSA valid", TYPE_TLSA.String(), "3 1 1 385cfdbc00ec32031699460779c15099b2bba3cad0e440fffb08e10df0acb9e1", false},
+		{"TLSA digest length", TYPE_TLSA.String(), "3 1 1 385cfdbc", true},
+
+		{"DS valid", TYPE_DS.String(), "60485 5 1 2bb183af5f22588179a53b0a98631fad1a292118", false},
+		{"DS unknown digest type", TYPE_DS.String(), "60485 5 9 2bb183af5f22588179a53b0a98631fad1a292118", true},
+
+		{"HTTPS alias mode", TYPE_HTTPS.String(), "0 cdn.unit.tests.", false},
+		{"HTTPS params out of order", TYPE_HTTPS.String(), "1 . port=443 alpn=h2", true},
+
+		{"ALIAS valid FQDN", TYPE_ALIAS.String(), "www.unit.tests.", false},
+		{"OPENPGPKEY not base64", TYPE_OPENPGPKEY.String(), "not base64", true},
 	}
 
 	for _, c := range cases {
@@ -1064,6 +1387,47 @@ func TestValidateValueString(t *testing.T) {
 	}
 }
 
+// TestValidateValueString_FieldErrors checks out of range fields are reported
+// by the field validators, not as a value not matching the format.
+func TestValidateValueString_FieldErrors(t *testing.T) {
+	cases := []struct {
+		rtype string
+		value string
+		want  string
+	}{
+		{TYPE_URLFWD.String(), "303 2 / 0 http://www.unit.tests", "code should be 0, 301 or 302"},
+		{TYPE_URLFWD.String(), "301 3 / 0 http://www.unit.tests", "masking should be 0, 1 or 2"},
+		{TYPE_URLFWD.String(), "301 2 / 2 http://www.unit.tests", "query should be 0 or 1"},
+		{TYPE_LOC.String(), "31 S 115 E -100000.01", "altitude should be between -100000.00 and 42849672.95"},
+		// numbers that don't fit an int are out of range, not a panic
+		{TYPE_URLFWD.String(), "99999999999999999999 0 / 0 https://x.com", "code should be 0, 301 or 302"},
+		{TYPE_URLFWD.String(), "301 99999999999999999999 / 0 https://x.com", "masking should be 0, 1 or 2"},
+		{TYPE_URLFWD.String(), "301 0 / 99999999999999999999 https://x.com", "query should be 0 or 1"},
+		{TYPE_SSHFP.String(), "1 99999999999999999999 ab", "fingerprint_type should be 1 (SHA-1) or 2 (SHA-256)"},
+		{TYPE_SSHFP.String(), "99999999999999999999 1 ab", "algorithm should be 1 (RSA), 2 (DSA), 3 (ECDSA) or 4 (Ed25519)"},
+		{TYPE_NAPTR.String(), `99999999999999999999 100 "S" "SIP+D2U" "!^.*$!sip:info@example.com!" .`, "order should be between 0 and 65535"},
+		{TYPE_NAPTR.String(), `10 99999999999999999999 "S" "SIP+D2U" "!^.*$!sip:info@example.com!" .`, "preference should be between 0 and 65535"},
+		{TYPE_LOC.String(), "99999999999999999999 S 115 E 0", "lat_degrees should be between 0 and 90"},
+		{TYPE_LOC.String(), "31 99999999999999999999 S 115 E 0", "lat_minutes is out of range"},
+		{TYPE_LOC.String(), "31 S 115 E 0 99999999999999999999", "size is out of range"},
+		{TYPE_TLSA.String(), "99999999999999999999 1 1 ab", "certificate_usage should be 0, 1, 2 or 3"},
+		{TYPE_TLSA.String(), "3 99999999999999999999 1 ab", "selector should be 0 or 1"},
+		{TYPE_TLSA.String(), "3 1 99999999999999999999 ab", "matching_type should be 0, 1 or 2"},
+		{TYPE_DS.String(), "99999999999999999999 8 2 ab", "key_tag should be between 0 and 65535"},
+		{TYPE_DS.String(), "60485 99999999999999999999 2 ab", "algorithm should be between 1 and 255"},
+		{TYPE_DS.String(), "60485 8 99999999999999999999 ab", "digest_type should be 1 (SHA-1), 2 (SHA-256), 3 (GOST) or 4 (SHA-384)"},
+		{TYPE_HTTPS.String(), "99999999999999999999 example.com.", "svcpriority should be between 0 and 65535"},
+		{TYPE_SVCB.String(), "99999999999999999999 example.com.", "svcpriority should be between 0 and 65535"},
+	}
+
+	for _, c := range cases {
+		err := ValidateValueString(c.rtype, c.value)
+		if err == nil || err.Error() != c.want {
+			t.Errorf("%s=%q: got error %v, want %q", c.rtype, c.value, err, c.want)
+		}
+	}
+}
+
 /**** Other Tests ****/
 // TestReadAllChecks check result of all tests.
 func TestRecord_CheckAllChecks(t *testing.T) {
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SPF_LOOKUP_LIMIT is the number of mechanisms and modifiers doing a DNS
+// lookup an SPF policy may have, see RFC 7208 section 4.6.4.
+const SPF_LOOKUP_LIMIT = 10
+
+var (
+	regSPFModifier   = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9._-]*)=(.*)$`)
+	regSPFMechanism  = regexp.MustCompile(`^([+\-~?]?)([a-zA-Z0-9]+)(:[^/]*)?(/.*)?$`)
+	regSPFDualCIDR   = regexp.MustCompile(`^(/(\d+))?(//(\d+))?$`)
+	regSPFDomain     = regexp.MustCompile(`^([a-zA-Z0-9_]([a-zA-Z0-9_-]*[a-zA-Z0-9_])?\.)+[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?\.?$`)
+	regSPFMacroChars = regexp.MustCompile(`^[\x21-\x7e]+$`)
+)
+
+// isSPF reports if the text is an SPF policy.
+func isSPF(text string) bool {
+	return text == "v=spf1" || strings.HasPrefix(text, "v=spf1 ")
+}
+
+// validateSPFDomain checks the domain-spec of a mechanism or modifier, specs
+// with macros are only checked for their characters.
+func validateSPFDomain(term, domain string) error {
+	if strings.Contains(domain, "%") && regSPFMacroChars.MatchString(domain) {
+		return nil
+	}
+	if !regSPFDomain.MatchString(domain) {
+		return fmt.Errorf("%s should have a valid domain, got %q", term, domain)
+	}
+	return nil
+}
+
+// validateSPFCIDR checks a prefix length is at most maxBits bits.
+func validateSPFCIDR(term, length string, maxBits int) error {
+	if length == "" {
+		return nil
+	}
+	if bits, err := strconv.Atoi(length); err != nil || bits < 0 || bits > maxBits {
+		return fmt.Errorf("%s should have a prefix length between 0 and %d", term, maxBits)
+	}
+	return nil
+}
+
+// validateSPF checks the syntax of the terms of an SPF policy, see RFC 7208
+// section 12. It has to end with an all mechanism or redirect to another
+// policy.
+func validateSPF(text string) error {
+	terms := strings.Fields(text)
+	if len(terms) == 0 || terms[0] != "v=spf1" {
+		return fmt.Errorf("value should start with v=spf1")
+	}
+
+	hasAll := false
+	modifiers := map[string]bool{}
+	for _, term := range terms[1:] {
+		if match := regSPFModifier.FindStringSubmatch(term); match != nil {
+			name := strings.ToLower(match[1])
+			switch name {
+			case "redirect", "exp":
+				if modifiers[name] {
+					return fmt.Errorf("modifier %s should be used at most once", name)
+				}
+				if err := validateSPFDomain(term, match[2]); err != nil {
+					return err
+				}
+			}
+			modifiers[name] = true
+			continue
+		}
+
+		match := regSPFMechanism.FindStringSubmatch(term)
+		if match == nil {
+			return fmt.Errorf("%q is not a valid mechanism or modifier", term)
+		}
+		name, domain, cidr := strings.ToLower(match[2]), strings.TrimPrefix(match[3], ":"), match[4]
+		hasDomain := match[3] != ""
+
+		switch name {
+		case "all":
+			if hasDomain || cidr != "" {
+				return fmt.Errorf("%s takes no arguments", term)
+			}
+			hasAll = true
+		case "include", "exists":
+			if !hasDomain || cidr != "" {
+				return fmt.Errorf("%s should be %s:<domain>", term, name)
+			}
+			if err := validateSPFDomain(term, domain); err != nil {
+				return err
+			}
+		case "a", "mx", "ptr":
+			if hasDomain {
+				if err := validateSPFDomain(term, domain); err != nil {
+					return err
+				}
+			}
+			cidrs := regSPFDualCIDR.FindStringSubmatch(cidr)
+			if cidrs == nil || (name == "ptr" && cidr != "") {
+				return fmt.Errorf("%s has an invalid prefix length", term)
+			}
+			if err := validateSPFCIDR(term, cidrs[2], 32); err != nil {
+				return err
+			}
+			if err := validateSPFCIDR(term, cidrs[4], 128); err != nil {
+				return err
+			}
+		case "ip4", "ip6":
+			ip := net.ParseIP(domain)
+			if !hasDomain || ip == nil || (name == "ip4") != (ip.To4() != nil && !strings.Contains(domain, ":")) {
+				return fmt.Errorf("%s should be %s:<address>[/<prefix length>]", term, name)
+			}
+			maxBits := 32
+			if name == "ip6" {
+				maxBits = 128
+			}
+			if cidr == "/" {
+				return fmt.Errorf("%s should have a prefix length after the slash", term)
+			}
+			if err := validateSPFCIDR(term, strings.TrimPrefix(cidr, "/"), maxBits); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%q is not a valid mechanism or modifier", term)
+		}
+	}
+
+	if !hasAll && !modifiers["redirect"] {
+		return fmt.Errorf("value should contain a part for ALL, or a redirect modifier")
+	}
+	return nil
+}
+
+// SPFLookups returns the number of mechanisms and modifiers of an SPF policy
+// that do a DNS lookup, 0 for values that are not an SPF policy. Lookups done
+// by included policies are not counted.
+func SPFLookups(value string) int {
+	text := unchunk(value)
+	if !isSPF(text) {
+		return 0
+	}
+
+	lookups := 0
+	for _, term := range strings.Fields(text)[1:] {
+		if match := regSPFModifier.FindStringSubmatch(term); match != nil {
+			if strings.ToLower(match[1]) == "redirect" {
+				lookups++
+			}
+			continue
+		}
+		if match := regSPFMechanism.FindStringSubmatch(term); match != nil {
+			switch strings.ToLower(match[2]) {
+			case "include", "a", "mx", "ptr", "exists":
+				lookups++
+			}
+		}
+	}
+	return lookups
+}
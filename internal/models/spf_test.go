@@ -0,0 +1,65 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSPF(t *testing.T) {
+
+	cases := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"v=spf1 -all", false},
+		{"v=spf1 ip4:192.168.0.1/16 ip6:2001:db8::/32 a mx:mail.unit.tests/24//64 ?all", false},
+		{"v=spf1 include:_spf.google.com ~all", false},
+		{"v=spf1 exists:%{i}._spf.%{d} -all", false},
+		{"v=spf1 ptr:unit.tests +all", false},
+		{"v=spf1 a mx redirect=_spf.unit.tests", false},
+		{"v=spf1 mx exp=explain.unit.tests foo=bar -ALL", false},
+
+		{"v=spf2 -all", true},
+		{"v=spf1", true},
+		{"v=spf1 include:_spf.google.com", true},
+		{"v=spf1 include -all", true},
+		{"v=spf1 include:unit -all", true},
+		{"v=spf1 ip4:192.168.0.1/33 -all", true},
+		{"v=spf1 ip4:2001:db8::1 -all", true},
+		{"v=spf1 ip6:192.168.0.1 -all", true},
+		{"v=spf1 ip6:2001:db8::/129 -all", true},
+		{"v=spf1 ip4:192.168.0.1/ -all", true},
+		{"v=spf1 ip4:192.168.0.1/-1 -all", true},
+		{"v=spf1 a/24//129 -all", true},
+		{"v=spf1 ptr/24 -all", true},
+		{"v=spf1 all:unit.tests", true},
+		{"v=spf1 mxx -all", true},
+		{"v=spf1 redirect=a.tests redirect=b.tests", true},
+		{"v=spf1 ip4:192.168.0.1/16-all", true},
+	}
+	for _, c := range cases {
+		err := ValidateValueString(TYPE_SPF.String(), c.value)
+		if c.wantErr && err == nil {
+			t.Errorf("expected error for %q, got nil", c.value)
+		}
+		if !c.wantErr && err != nil {
+			t.Errorf("unexpected error for %q: %s", c.value, err)
+		}
+	}
+}
+
+func TestSPFLookups(t *testing.T) {
+
+	cases := map[string]int{
+		"v=spf1 ip4:192.168.0.1 -all":                                     0,
+		"v=spf1 a mx ptr include:a.tests exists:b.tests redirect=c.tests": 6,
+		"v=spf1" + strings.Repeat(" include:a.tests", 11) + " -all":       11,
+		"not an spf policy": 0,
+		`"v=spf1 include:a.tests " "include:b.tests -all"`: 2,
+	}
+	for value, want := range cases {
+		if got := SPFLookups(value); got != want {
+			t.Errorf("SPFLookups(%q) = %d, want %d", value, got, want)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestSubdomain_UpdateYaml(t *testing.T) {
@@ -261,7 +263,7 @@ func TestSubdomain_CreateRollbackExistingSubdomain(t *testing.T) {
 		t.Errorf("%s", err.Error())
 	}
 
-	// Apex ('') already has multiple types (A, SSHFP, NS, CAA); add MX and roll back
+	// Apex ('') already has multiple types (A, SSHFP, NS, CAA, ALIAS); add MX and roll back
 	sub, err := xZone.FindSubdomain("")
 	if err != nil {
 		t.Errorf("FindSubdomain throws an error: %s", err)
@@ -338,3 +340,174 @@ func TestSubdomain_CreateType(t *testing.T) {
 	}
 
 }
+
+func TestSubdomain_UpdateYaml_PreservesUnknownKeys(t *testing.T) {
+
+	xZone, err := zoneFromYaml(UNIT_FILE_DEFAULT)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	// Re-encode records with geo and octodns keys, those should survive unchanged
+	records := []struct {
+		name  string
+		rtype RType
+	}{
+		{"", TYPE_A},
+		{"excluded", TYPE_CNAME},
+		{"ignored", TYPE_A},
+		{"included", TYPE_CNAME},
+	}
+	for _, r := range records {
+		sub, err := xZone.FindSubdomain(r.name)
+		if err != nil {
+			t.Fatalf("FindSubdomain throws an error: %s", err)
+		}
+		if _, err = sub.GetType(r.rtype.String()); err != nil {
+			t.Fatalf("GetType throws an error: %s", err)
+		}
+		if err = sub.UpdateYaml(); err != nil {
+			t.Fatalf("UpdateYaml throws an error: %s", err)
+		}
+	}
+
+	compareZoneOutputWithFile(t, &xZone, UNIT_FILE_DEFAULT)
+
+}
+
+func TestRecord_UpdateYaml_UnknownKeys(t *testing.T) {
+
+	xZone := Zone{}
+	err := xZone.ReadYaml([]byte(`www:
+  dynamic:
+    pools:
+      one:
+        values:
+        - value: 1.1.1.1
+    rules:
+    - pool: one
+  octodns:
+    cloudflare:
+      proxied: true
+    route53:
+      healthcheck:
+        measure_latency: false
+  ttl: 300
+  type: A
+  value: 2.2.2.2
+`))
+	if err != nil {
+		t.Fatalf("ReadYaml error: %s", err)
+	}
+
+	sub, err := xZone.FindSubdomain("www")
+	if err != nil {
+		t.Fatalf("FindSubdomain throws an error: %s", err)
+	}
+	rt, err := sub.GetType(TYPE_A.String())
+	if err != nil {
+		t.Fatalf("GetType throws an error: %s", err)
+	}
+
+	rt.TTL = 600
+	rt.ClearValues()
+	_ = rt.AddValueFromString("3.3.3.3")
+	rt.Octodns.Delete("cloudflare")
+
+	if err = sub.UpdateYaml(); err != nil {
+		t.Fatalf("UpdateYaml throws an error: %s", err)
+	}
+
+	out, err := xZone.WriteYaml()
+	if err != nil {
+		t.Fatalf("WriteYaml error: %s", err)
+	}
+
+	want := `www:
+  dynamic:
+    pools:
+      one:
+        values:
+          - value: 1.1.1.1
+    rules:
+      - pool: one
+  octodns:
+    route53:
+      healthcheck:
+        measure_latency: false
+  ttl: 600
+  type: A
+  value: 3.3.3.3
+`
+	if !bytes.Equal(out, []byte(want)) {
+		t.Errorf("Output is not equal:\n%s", cmp.Diff(want, string(out)))
+	}
+
+}
+
+func TestRecord_UpdateYaml_Healthcheck(t *testing.T) {
+
+	xZone := Zone{}
+	err := xZone.ReadYaml([]byte(`www:
+  octodns:
+    healthcheck:
+      host: www.unit.tests
+      path: /_health
+      port: 8080
+      protocol: HTTP
+    route53:
+      healthcheck:
+        measure_latency: false
+  type: A
+  value: 2.2.2.2
+`))
+	if err != nil {
+		t.Fatalf("ReadYaml error: %s", err)
+	}
+
+	sub, err := xZone.FindSubdomain("www")
+	if err != nil {
+		t.Fatalf("FindSubdomain throws an error: %s", err)
+	}
+	rt, err := sub.GetType(TYPE_A.String())
+	if err != nil {
+		t.Fatalf("GetType throws an error: %s", err)
+	}
+
+	want := map[string]interface{}{"host": "www.unit.tests", "path": "/_health", "port": 8080, "protocol": "HTTP"}
+	got, ok, err := rt.Octodns.Get("healthcheck")
+	if err != nil || !ok {
+		t.Fatalf("Get healthcheck: %v, %v", ok, err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected healthcheck (-want +got):\n%s", diff)
+	}
+
+	if err = rt.Octodns.Set("healthcheck", map[string]interface{}{"protocol": "TCP", "port": 443}); err != nil {
+		t.Fatalf("Set healthcheck: %s", err)
+	}
+	if err = sub.UpdateYaml(); err != nil {
+		t.Fatalf("UpdateYaml throws an error: %s", err)
+	}
+
+	out, err := xZone.WriteYaml()
+	if err != nil {
+		t.Fatalf("WriteYaml error: %s", err)
+	}
+
+	wantOut := `www:
+  octodns:
+    healthcheck:
+      port: 443
+      protocol: TCP
+    route53:
+      healthcheck:
+        measure_latency: false
+  type: A
+  value: 2.2.2.2
+`
+	if !bytes.Equal(out, []byte(wantOut)) {
+		t.Errorf("Output is not equal:\n%s", cmp.Diff(wantOut, string(out)))
+	}
+
+}
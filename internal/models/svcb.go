@@ -0,0 +1,215 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SVCB_PARAM_KEYS are the known SvcParamKeys in the order of their key
+// number, values are written in this order, followed by the keyNNNNN keys.
+var SVCB_PARAM_KEYS = []string{"mandatory", "alpn", "no-default-alpn", "port", "ipv4hint", "ech", "ipv6hint"}
+
+var regSvcParamKey = regexp.MustCompile(`^key(\d{1,5})$`)
+
+// svcParamKeyNumber returns the number of a SvcParamKey, or an error when the
+// key is unknown.
+func svcParamKeyNumber(key string) (int, error) {
+	for i, known := range SVCB_PARAM_KEYS {
+		if key == known {
+			return i, nil
+		}
+	}
+	if match := regSvcParamKey.FindStringSubmatch(key); match != nil {
+		number, _ := strconv.Atoi(match[1])
+		if number < len(SVCB_PARAM_KEYS) {
+			return 0, fmt.Errorf("svcparam %s should be written as %s", key, SVCB_PARAM_KEYS[number])
+		}
+		if number <= 65535 {
+			return number, nil
+		}
+	}
+	return 0, fmt.Errorf("svcparam %s is not a known key or keyNNNNN", key)
+}
+
+var regStringSVCB = regexp.MustCompile(`^(?P<priority>\d+) (?P<target>[^ ]+)(?P<params>( [^ ]+)*)$`)
+
+func (r *RecordValue) UnmarshalStringSVCB(value string) error {
+	parts, err := regexToMap(value, regStringSVCB)
+	if err != nil {
+		return err
+	}
+
+	r.SvcPriority = RefStringAsInt(parts["priority"])
+	r.TargetName = RefString(parts["target"])
+	r.SvcParams = nil
+
+	for _, param := range strings.Fields(parts["params"]) {
+		key, paramValue, hasValue := strings.Cut(param, "=")
+		if _, ok := r.SvcParams[key]; ok {
+			return fmt.Errorf("svcparam %s is set more than once", key)
+		}
+		if r.SvcParams == nil {
+			r.SvcParams = map[string]interface{}{}
+		}
+		switch {
+		case key == "no-default-alpn":
+			if hasValue {
+				return fmt.Errorf("svcparam no-default-alpn takes no value")
+			}
+			r.SvcParams[key] = nil
+		case !hasValue || paramValue == "":
+			return fmt.Errorf("svcparam %s needs a value", key)
+		case key == "port":
+			port, err := strconv.Atoi(paramValue)
+			if err != nil {
+				return fmt.Errorf("svcparam port should be a number")
+			}
+			r.SvcParams[key] = port
+		case key == "mandatory" || key == "alpn" || key == "ipv4hint" || key == "ipv6hint":
+			list := []interface{}{}
+			for _, item := range strings.Split(paramValue, ",") {
+				list = append(list, item)
+			}
+			r.SvcParams[key] = list
+		default:
+			r.SvcParams[key] = paramValue
+		}
+	}
+
+	if err := r.validateSVCB(); err != nil {
+		return err
+	}
+
+	// Params in key order, with plain numbers, are written back the same way
+	if rendered := r.StringSVCB(); rendered != value {
+		return fmt.Errorf("value should be written as %q, with the svcparams in key order", rendered)
+	}
+	return nil
+}
+
+// validateSVCB checks the priority, target and params of an SVCB or HTTPS
+// value.
+func (r *RecordValue) validateSVCB() error {
+	if r.SvcPriority == nil || *r.SvcPriority < 0 || *r.SvcPriority > 65535 {
+		return fmt.Errorf("svcpriority should be between 0 and 65535")
+	}
+	if *r.TargetName != "." && r.validateFQDN(*r.TargetName, true) != nil {
+		return fmt.Errorf("targetname should be . or a fqdn ending with a dot")
+	}
+	if *r.SvcPriority == 0 && len(r.SvcParams) > 0 {
+		return fmt.Errorf("svcparams are not allowed with svcpriority 0 (alias mode)")
+	}
+
+	for key, paramValue := range r.SvcParams {
+		if _, err := svcParamKeyNumber(key); err != nil {
+			return err
+		}
+		switch key {
+		case "mandatory", "alpn":
+			if _, err := svcParamList(key, paramValue); err != nil {
+				return err
+			}
+		case "ipv4hint", "ipv6hint":
+			list, err := svcParamList(key, paramValue)
+			if err != nil {
+				return err
+			}
+			for _, ip := range list {
+				if key == "ipv4hint" && r.validateIPV4(ip) != nil || key == "ipv6hint" && r.validateIPV6(ip) != nil {
+					return fmt.Errorf("svcparam %s has invalid address %s", key, ip)
+				}
+			}
+		case "port":
+			port, ok := paramValue.(int)
+			if !ok || port < 0 || port > 65535 {
+				return fmt.Errorf("svcparam port should be between 0 and 65535")
+			}
+		case "no-default-alpn":
+			if paramValue != nil {
+				return fmt.Errorf("svcparam no-default-alpn takes no value")
+			}
+		default:
+			if _, ok := paramValue.(string); !ok {
+				return fmt.Errorf("svcparam %s should be a string", key)
+			}
+		}
+	}
+
+	if _, ok := r.SvcParams["no-default-alpn"]; ok {
+		if _, ok := r.SvcParams["alpn"]; !ok {
+			return fmt.Errorf("svcparam no-default-alpn needs alpn")
+		}
+	}
+	if mandatory, ok := r.SvcParams["mandatory"]; ok {
+		keys, _ := svcParamList("mandatory", mandatory)
+		for _, key := range keys {
+			if key == "mandatory" {
+				return fmt.Errorf("svcparam mandatory must not list itself")
+			}
+			if _, ok := r.SvcParams[key]; !ok {
+				return fmt.Errorf("mandatory svcparam %s is missing", key)
+			}
+		}
+	}
+	return nil
+}
+
+// svcParamList returns a list param as strings.
+func svcParamList(key string, value interface{}) ([]string, error) {
+	items, ok := value.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("svcparam %s should be a list", key)
+	}
+	list := []string{}
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("svcparam %s should be a list of strings", key)
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+func (r *RecordValue) StringSVCB() string {
+	if r.SvcPriority == nil || r.TargetName == nil {
+		return ""
+	}
+
+	keys := []string{}
+	for key := range r.SvcParams {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, errA := svcParamKeyNumber(keys[i])
+		b, errB := svcParamKeyNumber(keys[j])
+		if errA != nil || errB != nil {
+			// unknown keys go last
+			if (errA == nil) != (errB == nil) {
+				return errA == nil
+			}
+			return keys[i] < keys[j]
+		}
+		return a < b
+	})
+
+	ret := fmt.Sprintf("%d %s", *r.SvcPriority, *r.TargetName)
+	for _, key := range keys {
+		switch paramValue := r.SvcParams[key].(type) {
+		case nil:
+			ret += " " + key
+		case []interface{}:
+			list := []string{}
+			for _, item := range paramValue {
+				list = append(list, fmt.Sprint(item))
+			}
+			ret += fmt.Sprintf(" %s=%s", key, strings.Join(list, ","))
+		default:
+			ret += fmt.Sprintf(" %s=%v", key, paramValue)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// TXT_CHUNK_SIZE is the maximum length in bytes of a single character string
+// of a TXT or SPF value.
+const TXT_CHUNK_SIZE = 255
+
+// CHUNKED_TYPES are the record types whose values are character strings,
+// written by octoDNS as quoted chunks when they are longer than a single
+// string.
+var CHUNKED_TYPES = []string{TYPE_SPF.String(), TYPE_TXT.String()}
+
+func SupportsChunking(rtype string) bool {
+	return slices.Contains(CHUNKED_TYPES, rtype)
+}
+
+var (
+	regChunkedValue = regexp.MustCompile(`^"(?:[^"\\]|\\.)*"(?: +"(?:[^"\\]|\\.)*")*$`)
+	regChunk        = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+	regEscape       = regexp.MustCompile(`\\(.)`)
+)
+
+// IsChunked reports if the value is written as quoted chunks, eq:
+// `"v=DKIM1\; k=rsa\; p=MIIB..." "...IDAQAB"`.
+func IsChunked(value string) bool {
+	return regChunkedValue.MatchString(value)
+}
+
+// valueChunks returns the contents of the chunks of a chunked value, with the
+// escapes of the chunks undone. Values that are not chunked are returned as
+// a single chunk.
+func valueChunks(value string) []string {
+	if !IsChunked(value) {
+		return []string{value}
+	}
+	chunks := []string{}
+	for _, match := range regChunk.FindAllStringSubmatch(value, -1) {
+		chunks = append(chunks, regEscape.ReplaceAllString(match[1], "$1"))
+	}
+	return chunks
+}
+
+// unchunk returns the text of the value, the chunks are joined without
+// spaces as resolvers do.
+func unchunk(value string) string {
+	return strings.Join(valueChunks(value), "")
+}
+
+// ChunkValue splits the value into quoted chunks of at most TXT_CHUNK_SIZE
+// bytes, the format octoDNS uses for long values. Quotes are escaped, escape
+// sequences and multibyte characters are never split.
+func ChunkValue(value string) string {
+	value = strings.ReplaceAll(value, `"`, `\"`)
+
+	chunks := []string{}
+	for len(value) > 0 {
+		end := min(len(value), TXT_CHUNK_SIZE)
+		for end < len(value) && !utf8.RuneStart(value[end]) {
+			end--
+		}
+		if backslashes := len(value[:end]) - len(strings.TrimRight(value[:end], `\`)); backslashes%2 == 1 {
+			end--
+		}
+		chunks = append(chunks, value[:end])
+		value = value[end:]
+	}
+	return `"` + strings.Join(chunks, `" "`) + `"`
+}
+
+// EscapeSemicolons escapes the semicolons of the value that are not escaped
+// yet, octoDNS requires them to be escaped in TXT and SPF values.
+func EscapeSemicolons(value string) string {
+	var b strings.Builder
+	escaped := false
+	for _, c := range value {
+		if c == ';' && !escaped {
+			b.WriteRune('\\')
+		}
+		escaped = c == '\\' && !escaped
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// UnmarshalStringTXT checks the value of a TXT record: every chunk has to fit
+// in a single character string, and SPF policies published as TXT record have
+// to be valid.
+func (r *RecordValue) UnmarshalStringTXT(value string) error {
+	if IsChunked(value) {
+		for i, chunk := range valueChunks(value) {
+			if len(chunk) > TXT_CHUNK_SIZE {
+				return fmt.Errorf("chunk %d is %d bytes, longer than %d", i+1, len(chunk), TXT_CHUNK_SIZE)
+			}
+		}
+	}
+	if text := unchunk(value); isSPF(text) {
+		if err := validateSPF(text); err != nil {
+			return err
+		}
+	}
+
+	return r.UnmarshalString(value)
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChunkValue(t *testing.T) {
+
+	long := strings.Repeat("a", 300)
+	got := ChunkValue(long)
+	want := `"` + strings.Repeat("a", 255) + `" "` + strings.Repeat("a", 45) + `"`
+	if got != want {
+		t.Errorf("ChunkValue(%d bytes) = %q, want %q", len(long), got, want)
+	}
+	if !IsChunked(got) {
+		t.Errorf("IsChunked(%q) = false, want true", got)
+	}
+	if unchunk(got) != long {
+		t.Errorf("unchunk does not give the value back")
+	}
+
+	// Escapes and multibyte characters are not split
+	for _, value := range []string{
+		strings.Repeat("a", 254) + `\;` + strings.Repeat("b", 10),
+		strings.Repeat("a", 254) + `"` + strings.Repeat("b", 10),
+		strings.Repeat("a", 254) + "é" + strings.Repeat("b", 10),
+	} {
+		chunked := ChunkValue(value)
+		if err := ValidateValueString(TYPE_TXT.String(), chunked); err != nil {
+			t.Errorf("chunked value %q is invalid: %s", chunked, err)
+		}
+		if got := strings.Join(valueChunks(chunked), ""); got != strings.ReplaceAll(value, `\;`, ";") {
+			t.Errorf("chunks of %q are %q", chunked, valueChunks(chunked))
+		}
+	}
+
+	if IsChunked(`"unterminated`) || IsChunked(`not "chunked"`) {
+		t.Errorf("IsChunked accepts values that are not chunked")
+	}
+}
+
+func TestEscapeSemicolons(t *testing.T) {
+
+	cases := map[string]string{
+		"v=DKIM1; k=rsa; p=MIIB":  `v=DKIM1\; k=rsa\; p=MIIB`,
+		`v=DKIM1\; k=rsa; p=MIIB`: `v=DKIM1\; k=rsa\; p=MIIB`,
+		`escaped backslash\\; x`:  `escaped backslash\\\; x`,
+		"no semicolons":           "no semicolons",
+	}
+	for value, want := range cases {
+		if got := EscapeSemicolons(value); got != want {
+			t.Errorf("EscapeSemicolons(%q) = %q, want %q", value, got, want)
+		}
+		if err := ValidateValueString(TYPE_TXT.String(), EscapeSemicolons(value)); err != nil {
+			t.Errorf("escaped value of %q is invalid: %s", value, err)
+		}
+	}
+}
+
+func TestRecord_Write_TXTChunks(t *testing.T) {
+
+	cases := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"long value", strings.Repeat("a", 300), false},
+		{"chunked", `"v=DKIM1\; k=rsa\; p=MIIB" "IDAQAB"`, false},
+		{"chunk too long", `"` + strings.Repeat("a", 256) + `" "b"`, true},
+		{"escaped semicolons fit in a chunk", `"` + strings.Repeat(`\;`, 255) + `"`, false},
+		{"unescaped semicolon in a chunk", `"v=DKIM1; k=rsa" "IDAQAB"`, true},
+		{"chunked SPF", `"v=spf1 include:_spf.google.com " "include:mailgun.org -all"`, false},
+		{"invalid chunked SPF", `"v=spf1 include:_spf.google.com " "include -all"`, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateValueString(TYPE_TXT.String(), c.value)
+			if c.wantErr && err == nil {
+				t.Errorf("expected error for %q, got nil", c.value)
+			}
+			if !c.wantErr && err != nil {
+				t.Errorf("unexpected error for %q: %s", c.value, err)
+			}
+		})
+	}
+}
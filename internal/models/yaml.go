@@ -0,0 +1,110 @@
+package models
+
+import (
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlMapping remembers the layout of a mapping node read from a zone file:
+// the order of its keys and the key/value pairs that are not modelled by one
+// of our structs. When the mapping is written back, unknown pairs are kept
+// unchanged and known keys stay where they were.
+type yamlMapping struct {
+	order  []string
+	extras []*yaml.Node
+}
+
+// readMapping collects the layout of a mapping node, every key not in known
+// is kept as an extra.
+func readMapping(node *yaml.Node, known []string) (m yamlMapping) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return m
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		m.order = append(m.order, key)
+		if !slices.Contains(known, key) {
+			m.extras = append(m.extras, node.Content[i], node.Content[i+1])
+		}
+	}
+
+	return m
+}
+
+// readOrder collects the order of the keys of a mapping node that is modelled
+// as a map, so every key is known.
+func readOrder(node *yaml.Node) yamlMapping {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return yamlMapping{}
+	}
+
+	keys := []string{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	return readMapping(node, keys)
+}
+
+// has reports if the mapping contained the key when it was read.
+func (m yamlMapping) has(key string) bool {
+	return slices.Contains(m.order, key)
+}
+
+func (m yamlMapping) hasExtras() bool {
+	return len(m.extras) > 0
+}
+
+// encode encodes value into a mapping node and merges the extras into it.
+// Keys that were read keep their original position, new keys are inserted
+// in front of the first key that sorts after them, so alphabetically sorted
+// files like the ones octoDNS dumps stay sorted.
+func (m yamlMapping) encode(value interface{}) (*yaml.Node, error) {
+	encoded := &yaml.Node{}
+	if err := encoded.Encode(value); err != nil {
+		return nil, err
+	}
+	if encoded.Kind != yaml.MappingNode {
+		return encoded, nil
+	}
+
+	pairs := map[string][]*yaml.Node{}
+	for i := 0; i+1 < len(m.extras); i += 2 {
+		pairs[m.extras[i].Value] = m.extras[i : i+2]
+	}
+	// Modelled values win over extras with the same key
+	for i := 0; i+1 < len(encoded.Content); i += 2 {
+		pairs[encoded.Content[i].Value] = encoded.Content[i : i+2]
+	}
+
+	node := &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  encoded.Tag,
+	}
+
+	for _, key := range m.order {
+		if pair, ok := pairs[key]; ok {
+			node.Content = append(node.Content, pair...)
+			delete(pairs, key)
+		}
+	}
+
+	for i := 0; i+1 < len(encoded.Content); i += 2 {
+		key := encoded.Content[i].Value
+		pair, ok := pairs[key]
+		if !ok {
+			continue
+		}
+		pos := len(node.Content)
+		for y := 0; y+1 < len(node.Content); y += 2 {
+			if node.Content[y].Value > key {
+				pos = y
+				break
+			}
+		}
+		node.Content = slices.Insert(node.Content, pos, pair...)
+	}
+
+	return node, nil
+}
@@ -16,6 +16,65 @@ type Zone struct {
 	scope string `yaml:"-"`
 	doc   yaml.Node
 	sha   string
+	// etag is the ETag of the response the zone was read from, used to
+	// check whether the cached zone is still current.
+	etag string
+
+	// created is set for a zone file that doesn't exist in the repository
+	// yet, deleted when the zone file is to be removed.
+	created bool
+	deleted bool
+}
+
+// NewZone returns an empty zone that is created in the repository when it
+// is committed.
+func NewZone(name, scope string) *Zone {
+	z := &Zone{name: name, scope: scope, created: true}
+	z.doc = yaml.Node{
+		Kind:    yaml.DocumentNode,
+		Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+	}
+	return z
+}
+
+// Delete marks the zone file to be removed from the repository when it is
+// committed.
+func (z *Zone) Delete() {
+	z.deleted = true
+}
+
+// RecordTypes returns the record types per subdomain as found in the zone
+// file, the apex is the empty subdomain.
+func (z *Zone) RecordTypes() (map[string][]string, error) {
+	if z.doc.Kind != yaml.DocumentNode || z.doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("zone.doc is not a document with a mapping")
+	}
+
+	typeOf := func(root *yaml.Node) string {
+		for i := 0; i < len(root.Content); i += 2 {
+			if root.Content[i].Value == "type" {
+				return strings.ToUpper(root.Content[i+1].Value)
+			}
+		}
+		return ""
+	}
+
+	result := map[string][]string{}
+	for i := 0; i < len(z.doc.Content[0].Content); i += 2 {
+		subdomain := z.doc.Content[0].Content[i].Value
+		content := z.doc.Content[0].Content[i+1]
+
+		result[subdomain] = []string{}
+		switch content.Kind {
+		case yaml.MappingNode:
+			result[subdomain] = append(result[subdomain], typeOf(content))
+		case yaml.SequenceNode:
+			for _, item := range content.Content {
+				result[subdomain] = append(result[subdomain], typeOf(item))
+			}
+		}
+	}
+	return result, nil
 }
 
 func (z *Zone) ReadYamlFile(filename string) error {
@@ -39,6 +98,15 @@ func (z *Zone) ReadYaml(content []byte) error {
 		return err
 	}
 
+	// An empty zone file holds no document or `{}`, start with an empty
+	// block mapping so added records are written the usual way.
+	if z.doc.Kind == 0 {
+		z.doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if root := z.doc.Content[0]; root.Kind == yaml.MappingNode && len(root.Content) == 0 {
+		root.Style = 0
+	}
+
 	return nil
 
 }
@@ -112,7 +180,7 @@ func (z *Zone) DeleteSubdomain(subdomain string) (err error) {
 		return
 	}
 
-	if len(z.doc.Content[0].Content) == 0 {
+	if z.doc.Content[0].Kind != yaml.MappingNode {
 		err = fmt.Errorf("error: %s", z.doc.Content[0].Value)
 		return
 	}
@@ -137,7 +205,7 @@ func (z *Zone) FindSubdomain(subdomain string) (record Subdomain, err error) {
 		return
 	}
 
-	if len(z.doc.Content[0].Content) == 0 {
+	if z.doc.Content[0].Kind != yaml.MappingNode {
 		err = fmt.Errorf("error: %s", z.doc.Content[0].Value)
 		return
 	}
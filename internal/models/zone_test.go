@@ -209,3 +209,24 @@ func TestZone_CreateSubdomain(t *testing.T) {
 	}
 
 }
+
+func TestZone_RecordTypes(t *testing.T) {
+	zone := NewZone("example.com", "default")
+	if err := zone.ReadYaml([]byte("'':\n  - type: NS\n    values: [ns1.example.net.]\n  - type: mx\n    value: {exchange: mx.example.com., preference: 10}\nwww:\n  type: A\n  value: 1.1.1.1\n")); err != nil {
+		t.Fatalf("ReadYaml failed: %s", err)
+	}
+
+	got, err := zone.RecordTypes()
+	if err != nil {
+		t.Fatalf("RecordTypes failed: %s", err)
+	}
+	want := map[string][]string{"": {"NS", "MX"}, "www": {"A"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected record types (-want +got):\n%s", diff)
+	}
+
+	empty, _ := NewZone("example.org", "default").RecordTypes()
+	if len(empty) != 0 {
+		t.Errorf("expected a new zone to be empty, got %v", empty)
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/topicusonderwijs/terraform-provider-octodns/internal/models"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ConfigZoneResource{}
+var _ resource.ResourceWithImportState = &ConfigZoneResource{}
+
+func NewConfigZoneResource() resource.Resource {
+	return &ConfigZoneResource{}
+}
+
+// ConfigZoneResource manages an entry under `zones:` in the OctoDNS config.
+type ConfigZoneResource struct {
+	client models.GitClient
+}
+
+func (r *ConfigZoneResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config_zone"
+}
+
+func (r *ConfigZoneResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Zone entry in the OctoDNS config file set by the provider `config_path`. " +
+			"Only the sources and targets of the zone are managed, other keys of the entry and the rest of the config, comments included, are left as they are.",
+
+		Attributes: map[string]schema.Attribute{
+			"zone": schema.StringAttribute{
+				MarkdownDescription: "Name of the zone, the trailing dot OctoDNS expects is added when missing. eq: example.com",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Config zone identifier",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sources": schema.ListAttribute{
+				MarkdownDescription: "Providers the records of the zone are read from",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+			"targets": schema.ListAttribute{
+				MarkdownDescription: "Providers the records of the zone are pushed to",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+		},
+	}
+}
+
+func (r *ConfigZoneResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	tflog.Trace(ctx, "- Resource Configure")
+
+	client, ok := req.ProviderData.(models.GitClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected models.GitClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func configZoneFromDataModel(data *ConfigZoneModel) models.ConfigZone {
+	return models.ConfigZone{
+		Name:    data.Zone.ValueString(),
+		Sources: stringValues(data.Sources),
+		Targets: stringValues(data.Targets),
+	}
+}
+
+// save applies the change to the config and flushes it. Must be called with
+// the client locked, FlushIfLast is called on every path.
+func (r *ConfigZoneResource) save(ctx context.Context, action string, change models.ConfigZoneChange) error {
+	config, err := r.client.GetConfig(ctx)
+	if err != nil {
+		_ = r.client.FlushIfLast(ctx)
+		return fmt.Errorf("could not retrieve config: %w", err)
+	}
+
+	if change.Delete {
+		err = config.DeleteZone(change.Zone.Name)
+	} else {
+		err = config.SetZone(change.Zone)
+	}
+	if err != nil {
+		_ = r.client.FlushIfLast(ctx)
+		return fmt.Errorf("unable to %s zone in config: %w", action, err)
+	}
+
+	r.client.MarkConfigDirty(ctx, config, fmt.Sprintf("chore(config): %s zone %s", action, change.Zone.Name), change)
+
+	// FlushIfLast does the InFlight.Add(-1) internally.
+	if err = r.client.FlushIfLast(ctx); err != nil {
+		return fmt.Errorf("could not save config: %w", err)
+	}
+	return nil
+}
+
+func (r *ConfigZoneResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	tflog.Trace(ctx, "- Resource Create")
+	var data *ConfigZoneModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Lock does the InFlight.Add(+1) BEFORE locking so all queued goroutines
+	// are counted. FlushIfLast owns the Add(-1) — do NOT defer it separately.
+	r.client.Lock()
+	defer r.client.Unlock()
+
+	config, err := r.client.GetConfig(ctx)
+	if err == nil {
+		_, err = config.GetZone(data.Zone.ValueString())
+		if err == nil {
+			err = fmt.Errorf("zone %s is already in the config, import it instead", data.Zone.ValueString())
+		} else if errors.Is(err, models.ErrConfigZoneNotFound) {
+			err = nil
+		}
+	}
+	if err != nil {
+		_ = r.client.FlushIfLast(ctx)
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not add zone to config: %s", err.Error()))
+		return
+	}
+
+	if err = r.save(ctx, "create", models.ConfigZoneChange{Zone: configZoneFromDataModel(data)}); err != nil {
+		resp.Diagnostics.AddError("Client Error", err.Error())
+		return
+	}
+
+	data.Id = types.StringValue(data.Zone.ValueString())
+	tflog.Trace(ctx, "created a resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ConfigZoneResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *ConfigZoneModel
+	tflog.Trace(ctx, "- Resource Read")
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Zone = data.Id
+
+	// Reads don't take part in batching, but must not race the writes to
+	// the zone cache.
+	r.client.LockRead()
+	defer r.client.Unlock()
+
+	config, err := r.client.GetConfig(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not retrieve config: %s", err.Error()))
+		return
+	}
+
+	cz, err := config.GetZone(data.Zone.ValueString())
+	if errors.Is(err, models.ErrConfigZoneNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read zone %s from config, got error: %s", data.Zone.ValueString(), err))
+		return
+	}
+
+	data.Sources = []types.String{}
+	for _, source := range cz.Sources {
+		data.Sources = append(data.Sources, types.StringValue(source))
+	}
+	data.Targets = []types.String{}
+	for _, target := range cz.Targets {
+		data.Targets = append(data.Targets, types.StringValue(target))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ConfigZoneResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *ConfigZoneModel
+	tflog.Trace(ctx, "- Resource Update")
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.client.Lock()
+	defer r.client.Unlock()
+
+	if err := r.save(ctx, "update", models.ConfigZoneChange{Zone: configZoneFromDataModel(data)}); err != nil {
+		resp.Diagnostics.AddError("Client Error", err.Error())
+		return
+	}
+
+	data.Id = types.StringValue(data.Zone.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ConfigZoneResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *ConfigZoneModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.client.Lock()
+	defer r.client.Unlock()
+
+	change := models.ConfigZoneChange{Zone: models.ConfigZone{Name: data.Zone.ValueString()}, Delete: true}
+	if err := r.save(ctx, "delete", change); err != nil {
+		resp.Diagnostics.AddError("Client Error", err.Error())
+		return
+	}
+
+	data.Id = types.StringNull()
+}
+
+func (r *ConfigZoneResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
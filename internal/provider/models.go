@@ -11,198 +11,426 @@ import (
 	"github.com/topicusonderwijs/terraform-provider-octodns/internal/models"
 )
 
-// SubdomainModel describes the data source data model.
+// ZoneDataModel describes the zone data source data model.
+type ZoneDataModel struct {
+	Zone       types.String     `tfsdk:"zone"`
+	Scope      types.String     `tfsdk:"scope"`
+	Id         types.String     `tfsdk:"id"`
+	Subdomains []SubdomainModel `tfsdk:"subdomains"`
+}
+
 type SubdomainModel struct {
-	Zone  types.String `tfsdk:"zone"`
-	Scope types.String `tfsdk:"scope"`
-	Name  types.String `tfsdk:"name"`
-	Id    types.String `tfsdk:"id"`
-	Type  []TypeModel  `tfsdk:"type"`
+	Name types.String `tfsdk:"name"`
+	Type []TypeModel  `tfsdk:"type"`
 }
 type TypeModel struct {
-	Type   types.String   `tfsdk:"type"`
-	Values []types.String `tfsdk:"values"`
-	TTL    types.Int64    `tfsdk:"ttl"`
+	Type     types.String   `tfsdk:"type"`
+	Values   []types.String `tfsdk:"values"`
+	TTL      types.Int64    `tfsdk:"ttl"`
+	Octodns  types.Object   `tfsdk:"octodns"`
+	Ignored  types.Bool     `tfsdk:"ignored"`
+	Included []types.String `tfsdk:"included"`
+	Excluded []types.String `tfsdk:"excluded"`
 }
 
 type RecordModel struct {
+	Zone     types.String   `tfsdk:"zone"`
+	Scope    types.String   `tfsdk:"scope"`
+	Name     types.String   `tfsdk:"name"`
+	Id       types.String   `tfsdk:"id"`
+	Values   []types.String `tfsdk:"values"`
+	TTL      types.Int64    `tfsdk:"ttl"`
+	Octodns  types.Object   `tfsdk:"octodns"`
+	Ignored  types.Bool     `tfsdk:"ignored"`
+	Included []types.String `tfsdk:"included"`
+	Excluded []types.String `tfsdk:"excluded"`
+}
+
+type ZoneModel struct {
+	Zone         types.String   `tfsdk:"zone"`
+	Scope        types.String   `tfsdk:"scope"`
+	Id           types.String   `tfsdk:"id"`
+	Nameservers  []types.String `tfsdk:"nameservers"`
+	TTL          types.Int64    `tfsdk:"ttl"`
+	ForceDestroy types.Bool     `tfsdk:"force_destroy"`
+}
+
+type ConfigZoneModel struct {
 	Zone    types.String   `tfsdk:"zone"`
-	Scope   types.String   `tfsdk:"scope"`
-	Name    types.String   `tfsdk:"name"`
 	Id      types.String   `tfsdk:"id"`
-	Values  []types.String `tfsdk:"values"`
-	TTL     types.Int64    `tfsdk:"ttl"`
-	Octodns types.Object   `tfsdk:"octodns"`
+	Sources []types.String `tfsdk:"sources"`
+	Targets []types.String `tfsdk:"targets"`
 }
 
-type OctodnsConfigModel struct {
-	Cloudflare types.Object `tfsdk:"cloudflare"`
-	AzureDNS   types.Object `tfsdk:"azuredns"`
+// StructuredRecordModel is the RecordModel of the record resources that
+// also take their values as objects with a field per part of the value.
+type StructuredRecordModel struct {
+	RecordModel
+	StructuredValues types.List `tfsdk:"structured_values"`
 }
 
-/*
-	func (o OctodnsConfigModel) HasConfig() bool {
-		if o.Cloudflare != nil || o.AzureDNS != nil {
-			return true
-		} else {
-			return false
-		}
-	}
-*/
-type OctodnsCloudflareModel struct {
-	Proxied types.Bool `tfsdk:"proxied"`
-	AutoTTL types.Bool `tfsdk:"auto_ttl"`
+// ChunkedRecordModel is the RecordModel of the TXT and SPF record resources,
+// which split long values into chunks and can escape semicolons.
+type ChunkedRecordModel struct {
+	RecordModel
+	EscapeSemicolons types.Bool `tfsdk:"escape_semicolons"`
+	SplitLongValues  types.Bool `tfsdk:"split_long_values"`
 }
 
-func (o OctodnsCloudflareModel) Attributes() (attributes map[string]attr.Type) {
+// DynamicRecordModel is the RecordModel of the A, AAAA and CNAME record
+// resources, which take dynamic config.
+type DynamicRecordModel struct {
+	RecordModel
+	Dynamic types.Object `tfsdk:"dynamic"`
+}
 
-	attributes = make(map[string]attr.Type)
+// GeoRecordModel is the RecordModel of the A and AAAA record resources,
+// which take legacy geo config next to dynamic config.
+type GeoRecordModel struct {
+	DynamicRecordModel
+	Geo types.Map `tfsdk:"geo"`
+}
 
-	attributes["proxied"] = types.BoolType
-	attributes["auto_ttl"] = types.BoolType
+// GenericRecordModel is the RecordModel of the octodns_record resource,
+// which has the record type as an attribute.
+type GenericRecordModel struct {
+	StructuredRecordModel
+	Dynamic          types.Object `tfsdk:"dynamic"`
+	EscapeSemicolons types.Bool   `tfsdk:"escape_semicolons"`
+	Geo              types.Map    `tfsdk:"geo"`
+	SplitLongValues  types.Bool   `tfsdk:"split_long_values"`
+	Type             types.String `tfsdk:"type"`
+}
 
-	return attributes
+// GeoElementType is the element type of the geo map.
+var GeoElementType = types.ListType{ElemType: types.StringType}
+
+// GeoToDataModel returns the geo config of the record, null when the record
+// has none.
+func GeoToDataModel(ctx context.Context, record *models.Record) (types.Map, diag.Diagnostics) {
+	if len(record.Geo) == 0 {
+		return types.MapNull(GeoElementType), nil
+	}
+	return types.MapValueFrom(ctx, GeoElementType, record.Geo)
 }
 
-type OctodnsAzureDNSModel struct {
-	HCInterval    types.Int64 `tfsdk:"hc_interval"`
-	HCTimeout     types.Int64 `tfsdk:"hc_timeout"`
-	HCNumFailures types.Int64 `tfsdk:"hc_numfailures"`
+// GeoFromDataModel returns the geo config, nil when it is null.
+func GeoFromDataModel(ctx context.Context, geo types.Map) (map[string][]string, diag.Diagnostics) {
+	if geo.IsNull() || geo.IsUnknown() {
+		return nil, nil
+	}
+	ret := map[string][]string{}
+	diags := geo.ElementsAs(ctx, &ret, false)
+	return ret, diags
 }
 
-func (o OctodnsAzureDNSModel) Attributes() (attributes map[string]attr.Type) {
+type DynamicModel struct {
+	Pools map[string]DynamicPoolModel `tfsdk:"pools"`
+	Rules []DynamicRuleModel          `tfsdk:"rules"`
+}
 
-	attributes = make(map[string]attr.Type)
+type DynamicPoolModel struct {
+	Fallback types.String            `tfsdk:"fallback"`
+	Values   []DynamicPoolValueModel `tfsdk:"values"`
+}
 
-	attributes["hc_interval"] = types.Int64Type
-	attributes["hc_timeout"] = types.Int64Type
-	attributes["hc_numfailures"] = types.Int64Type
+type DynamicPoolValueModel struct {
+	Value  types.String `tfsdk:"value"`
+	Weight types.Int64  `tfsdk:"weight"`
+	Status types.String `tfsdk:"status"`
+}
 
-	return attributes
+type DynamicRuleModel struct {
+	Pool    types.String   `tfsdk:"pool"`
+	Geos    []types.String `tfsdk:"geos"`
+	Subnets []types.String `tfsdk:"subnets"`
 }
 
-func RecordToDataModel(ctx context.Context, data *RecordModel, record *models.Record) diag.Diagnostics {
+// DynamicAttributeTypes returns the attribute types of the dynamic config
+// object.
+func DynamicAttributeTypes() map[string]attr.Type {
+	value := types.ObjectType{AttrTypes: map[string]attr.Type{
+		"value":  types.StringType,
+		"weight": types.Int64Type,
+		"status": types.StringType,
+	}}
+	pool := types.ObjectType{AttrTypes: map[string]attr.Type{
+		"fallback": types.StringType,
+		"values":   types.ListType{ElemType: value},
+	}}
+	rule := types.ObjectType{AttrTypes: map[string]attr.Type{
+		"pool":    types.StringType,
+		"geos":    types.ListType{ElemType: types.StringType},
+		"subnets": types.ListType{ElemType: types.StringType},
+	}}
+	return map[string]attr.Type{
+		"pools": types.MapType{ElemType: pool},
+		"rules": types.ListType{ElemType: rule},
+	}
+}
 
-	retDiags := diag.Diagnostics{}
-	var diags diag.Diagnostics
+// DynamicToDataModel returns the dynamic config of the record, null when the
+// record has none.
+func DynamicToDataModel(ctx context.Context, record *models.Record) (types.Object, diag.Diagnostics) {
+	if record.Dynamic == nil {
+		return types.ObjectNull(DynamicAttributeTypes()), nil
+	}
 
-	if record.TTL > 0 {
-		data.TTL = types.Int64Value(int64(record.TTL))
-	} else {
-		data.TTL = types.Int64Null()
+	stringsOrNil := func(values []string) []types.String {
+		if len(values) == 0 {
+			return nil
+		}
+		ret := []types.String{}
+		for _, v := range values {
+			ret = append(ret, types.StringValue(v))
+		}
+		return ret
 	}
 
-	data.Values = []types.String{}
-	for _, v := range record.ValuesAsString() {
-		data.Values = append(data.Values, types.StringValue(v))
+	data := DynamicModel{Pools: map[string]DynamicPoolModel{}, Rules: []DynamicRuleModel{}}
+	for name, pool := range record.Dynamic.Pools {
+		p := DynamicPoolModel{Fallback: types.StringNull(), Values: []DynamicPoolValueModel{}}
+		if pool.Fallback != "" {
+			p.Fallback = types.StringValue(pool.Fallback)
+		}
+		for _, v := range pool.Values {
+			value := DynamicPoolValueModel{Value: types.StringValue(v.Value), Weight: types.Int64Null(), Status: types.StringNull()}
+			if v.Weight > 0 {
+				value.Weight = types.Int64Value(int64(v.Weight))
+			}
+			if v.Status != "" {
+				value.Status = types.StringValue(v.Status)
+			}
+			p.Values = append(p.Values, value)
+		}
+		data.Pools[name] = p
+	}
+	for _, rule := range record.Dynamic.Rules {
+		data.Rules = append(data.Rules, DynamicRuleModel{
+			Pool:    types.StringValue(rule.Pool),
+			Geos:    stringsOrNil(rule.Geos),
+			Subnets: stringsOrNil(rule.Subnets),
+		})
 	}
 
-	octodnsTFObj := make(map[string]attr.Value)
+	return types.ObjectValueFrom(ctx, DynamicAttributeTypes(), data)
+}
 
-	Cloudflare := OctodnsCloudflareModel{}
-	AzureDNS := OctodnsAzureDNSModel{}
-	octodnsTFObj["cloudflare"] = types.ObjectNull(Cloudflare.Attributes())
-	octodnsTFObj["azuredns"] = types.ObjectNull(AzureDNS.Attributes())
+// DynamicFromDataModel returns the dynamic config, nil when it is null.
+func DynamicFromDataModel(ctx context.Context, obj types.Object) (*models.Dynamic, diag.Diagnostics) {
+	if obj.IsNull() || obj.IsUnknown() {
+		return nil, nil
+	}
 
-	if record.Octodns.Cloudflare != nil {
-		if record.Octodns.Cloudflare.Proxied {
-			Cloudflare.Proxied = types.BoolValue(true)
+	var data DynamicModel
+	diags := obj.As(ctx, &data, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	dynamic := &models.Dynamic{Pools: map[string]models.DynamicPool{}}
+	for name, pool := range data.Pools {
+		p := models.DynamicPool{Fallback: pool.Fallback.ValueString()}
+		for _, v := range pool.Values {
+			p.Values = append(p.Values, models.DynamicPoolValue{
+				Value:  v.Value.ValueString(),
+				Weight: int(v.Weight.ValueInt64()),
+				Status: v.Status.ValueString(),
+			})
+		}
+		dynamic.Pools[name] = p
+	}
+	for _, rule := range data.Rules {
+		r := models.DynamicRule{Pool: rule.Pool.ValueString()}
+		for _, geo := range rule.Geos {
+			r.Geos = append(r.Geos, geo.ValueString())
 		}
-		if record.Octodns.Cloudflare.AutoTTL {
-			Cloudflare.AutoTTL = types.BoolValue(true)
+		for _, subnet := range rule.Subnets {
+			r.Subnets = append(r.Subnets, subnet.ValueString())
 		}
-
-		octodnsTFObj["cloudflare"], diags = types.ObjectValueFrom(ctx, Cloudflare.Attributes(), Cloudflare)
-		retDiags.Append(diags...)
+		dynamic.Rules = append(dynamic.Rules, r)
 	}
 
-	if record.Octodns.AzureDNS != nil {
-		isSet := false
+	return dynamic, diags
+}
 
-		if record.Octodns.AzureDNS.Healthcheck.Interval > 0 {
-			AzureDNS.HCInterval = types.Int64Value(int64(record.Octodns.AzureDNS.Healthcheck.Interval))
-			isSet = true
+// ValueFieldsAttributeTypes returns the attribute types of a structured value
+// with the fields.
+func ValueFieldsAttributeTypes(fields []models.ValueField) map[string]attr.Type {
+	attributes := make(map[string]attr.Type)
+	for _, field := range fields {
+		switch field.Kind {
+		case models.VALUE_FIELD_INT:
+			attributes[field.Name] = types.Int64Type
+		case models.VALUE_FIELD_FLOAT:
+			attributes[field.Name] = types.Float64Type
+		default:
+			attributes[field.Name] = types.StringType
 		}
-		if record.Octodns.AzureDNS.Healthcheck.Timeout > 0 {
-			AzureDNS.HCTimeout = types.Int64Value(int64(record.Octodns.AzureDNS.Healthcheck.Timeout))
-			isSet = true
+	}
+	return attributes
+}
+
+// StructuredValueFields returns the fields of a structured value object, null
+// attributes are left out. known is false when an attribute is unknown.
+func StructuredValueFields(value attr.Value) (fields map[string]interface{}, known bool) {
+	obj, ok := value.(types.Object)
+	if !ok || obj.IsNull() || obj.IsUnknown() {
+		return nil, false
+	}
+
+	fields = map[string]interface{}{}
+	for name, v := range obj.Attributes() {
+		if v.IsUnknown() {
+			return nil, false
 		}
-		if record.Octodns.AzureDNS.Healthcheck.NumFailures > 0 {
-			AzureDNS.HCNumFailures = types.Int64Value(int64(record.Octodns.AzureDNS.Healthcheck.NumFailures))
-			isSet = true
+		if v.IsNull() {
+			continue
 		}
-		if isSet {
-			octodnsTFObj["azuredns"], diags = types.ObjectValueFrom(ctx, AzureDNS.Attributes(), AzureDNS)
-			retDiags.Append(diags...)
+		switch tv := v.(type) {
+		case types.String:
+			fields[name] = tv.ValueString()
+		case types.Int64:
+			fields[name] = int(tv.ValueInt64())
+		case types.Float64:
+			fields[name] = tv.ValueFloat64()
 		}
 	}
+	return fields, true
+}
 
-	if octodnsTFObj["cloudflare"].IsNull() && octodnsTFObj["azuredns"].IsNull() {
-		data.Octodns = types.ObjectNull(data.Octodns.AttributeTypes(ctx))
-	} else {
-		data.Octodns, diags = types.ObjectValue(data.Octodns.AttributeTypes(ctx), octodnsTFObj)
-		retDiags.Append(diags...)
-	}
+// StructuredValuesToDataModel returns the values of the record as a list of
+// structured value objects with the fields.
+func StructuredValuesToDataModel(record *models.Record, fields []models.ValueField) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	attrTypes := ValueFieldsAttributeTypes(fields)
+	elemType := types.ObjectType{AttrTypes: attrTypes}
+
+	elems := []attr.Value{}
+	for _, v := range record.Values {
+		got, err := v.Fields(record.Type)
+		if err != nil {
+			diags.AddError("Value Error", fmt.Sprintf("Unable to read structured %s value: %s", record.Type, err))
+			continue
+		}
 
-	return retDiags
+		attrs := map[string]attr.Value{}
+		for _, field := range fields {
+			value, ok := got[field.Name]
+			switch field.Kind {
+			case models.VALUE_FIELD_INT:
+				attrs[field.Name] = types.Int64Null()
+				if n, isInt := value.(int); ok && isInt {
+					attrs[field.Name] = types.Int64Value(int64(n))
+				}
+			case models.VALUE_FIELD_FLOAT:
+				attrs[field.Name] = types.Float64Null()
+				if n, isInt := value.(int); ok && isInt {
+					attrs[field.Name] = types.Float64Value(float64(n))
+				} else if f, isFloat := value.(float64); ok && isFloat {
+					attrs[field.Name] = types.Float64Value(f)
+				}
+			default:
+				attrs[field.Name] = types.StringNull()
+				if ok {
+					attrs[field.Name] = types.StringValue(fmt.Sprint(value))
+				}
+			}
+		}
+
+		obj, d := types.ObjectValue(attrTypes, attrs)
+		diags.Append(d...)
+		elems = append(elems, obj)
+	}
 
+	list, d := types.ListValue(elemType, elems)
+	diags.Append(d...)
+	return list, diags
 }
 
-func RecordFromDataModel(ctx context.Context, data *RecordModel, record *models.Record) (diags diag.Diagnostics) {
+// StructuredValuesFromDataModel replaces the values of the record with the
+// structured values.
+func StructuredValuesFromDataModel(values types.List, record *models.Record) (diags diag.Diagnostics) {
+	record.ClearValues()
+	for _, elem := range values.Elements() {
+		fields, known := StructuredValueFields(elem)
+		if !known {
+			continue
+		}
+		if err := record.AddValueFromFields(fields); err != nil {
+			diags.AddError("Value Error", fmt.Sprintf("Invalid value %v: %s", fields, err))
+		}
+	}
+	return
+}
 
-	record.Name = data.Name.ValueString()
-	record.TTL = int(data.TTL.ValueInt64())
+// stringsToDataModel returns the strings as a list attribute, null when there
+// are none.
+func stringsToDataModel(values []string) []types.String {
+	if len(values) == 0 {
+		return nil
+	}
+	list := []types.String{}
+	for _, v := range values {
+		list = append(list, types.StringValue(v))
+	}
+	return list
+}
 
-	record.ClearValues()
-	for _, v := range data.Values {
-		if err := record.AddValueFromString(v.ValueString()); err != nil {
-			diags.AddError("Value Error", fmt.Sprintf("Invalid value %q: %s", v.ValueString(), err))
+func stringsFromDataModel(list []types.String) []string {
+	values := []string{}
+	for _, v := range list {
+		if !v.IsNull() && !v.IsUnknown() {
+			values = append(values, v.ValueString())
 		}
 	}
+	return values
+}
 
-	record.Octodns = models.OctodnsRecordConfig{}
+func RecordToDataModel(ctx context.Context, data *RecordModel, record *models.Record) diag.Diagnostics {
 
-	if !data.Octodns.IsUnknown() && !data.Octodns.IsNull() {
+	retDiags := diag.Diagnostics{}
+	var diags diag.Diagnostics
 
-		var octodns OctodnsConfigModel
-		diags.Append(data.Octodns.As(ctx, &octodns, basetypes.ObjectAsOptions{})...)
+	if record.TTL > 0 {
+		data.TTL = types.Int64Value(int64(record.TTL))
+	} else {
+		data.TTL = types.Int64Null()
+	}
 
-		if !octodns.Cloudflare.IsUnknown() && !octodns.Cloudflare.IsNull() {
-			var dCF OctodnsCloudflareModel
-			diags.Append(octodns.Cloudflare.As(ctx, &dCF, basetypes.ObjectAsOptions{})...)
+	data.Values = []types.String{}
+	for _, v := range record.ValuesAsString() {
+		data.Values = append(data.Values, types.StringValue(v))
+	}
 
-			oCF := models.OctodnsCloudflare{}
-			if !dCF.Proxied.IsNull() {
-				oCF.Proxied = dCF.Proxied.ValueBool()
-			}
-			if !dCF.AutoTTL.IsNull() {
-				oCF.AutoTTL = dCF.AutoTTL.ValueBool()
-			}
+	data.Octodns, diags = OctodnsToDataModel(record.Octodns, nil)
+	retDiags.Append(diags...)
 
-			record.Octodns.Cloudflare = &oCF
+	data.Ignored = types.BoolValue(record.Ignored)
+	data.Included = stringsToDataModel(record.Included)
+	data.Excluded = stringsToDataModel(record.Excluded)
 
-		}
+	return retDiags
 
-		if !octodns.AzureDNS.IsUnknown() && !octodns.AzureDNS.IsNull() {
-			var dAZ OctodnsAzureDNSModel
-			diags.Append(octodns.AzureDNS.As(ctx, &dAZ, basetypes.ObjectAsOptions{})...)
+}
 
-			oAZ := models.OctodnsAzureDNS{Healthcheck: models.OctodnsAzureDNSHealthcheck{}}
+func RecordFromDataModel(ctx context.Context, data *RecordModel, record *models.Record) (diags diag.Diagnostics) {
 
-			if !dAZ.HCTimeout.IsNull() {
-				oAZ.Healthcheck.Timeout = int(dAZ.HCTimeout.ValueInt64())
-			}
-			if !dAZ.HCInterval.IsNull() {
-				oAZ.Healthcheck.Interval = int(dAZ.HCInterval.ValueInt64())
-			}
-			if !dAZ.HCNumFailures.IsNull() {
-				oAZ.Healthcheck.NumFailures = int(dAZ.HCNumFailures.ValueInt64())
-			}
+	record.Name = data.Name.ValueString()
+	record.TTL = int(data.TTL.ValueInt64())
 
-			record.Octodns.AzureDNS = &oAZ
+	record.ClearValues()
+	for _, v := range data.Values {
+		if err := record.AddValueFromString(v.ValueString()); err != nil {
+			diags.AddError("Value Error", fmt.Sprintf("Invalid value %q: %s", v.ValueString(), err))
 		}
-
 	}
 
+	diags.Append(OctodnsFromDataModel(data.Octodns, &record.Octodns)...)
+
+	record.Ignored = data.Ignored.ValueBool()
+	record.Included = stringsFromDataModel(data.Included)
+	record.Excluded = stringsFromDataModel(data.Excluded)
+
 	return
 }
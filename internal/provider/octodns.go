@@ -0,0 +1,501 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/topicusonderwijs/terraform-provider-octodns/internal/models"
+)
+
+type octodnsFieldKind int
+
+const (
+	octodnsBool octodnsFieldKind = iota
+	octodnsInt64
+	octodnsString
+)
+
+// octodnsField is an attribute of an octodns block, stored at Path in the
+// config of the block or under its name when Path is not set.
+type octodnsField struct {
+	Name             string
+	Path             []string
+	Kind             octodnsFieldKind
+	Description      string
+	Int64Validators  []validator.Int64
+	StringValidators []validator.String
+}
+
+// octodnsBlock is an attribute of the `octodns` attribute, holding the config
+// stored under the same key in the octodns config of the record.
+type octodnsBlock struct {
+	Key         string
+	Description string
+	Fields      []octodnsField
+}
+
+// octodnsBlocks are the provider specific configs modelled as attributes, the
+// schemas and conversions of the `octodns` attribute are generated from them.
+// Config of other providers can be set with `raw`.
+var octodnsBlocks = []octodnsBlock{
+	{
+		Key:         "azuredns",
+		Description: "Healthcheck configuration for [Azure provider](https://github.com/octodns/octodns-azure/?tab=readme-ov-file#healthchecks)",
+		Fields: []octodnsField{
+			{Name: "hc_interval", Path: []string{"healthcheck", "interval"}, Kind: octodnsInt64, Description: "Azure healthcheck interval"},
+			{Name: "hc_timeout", Path: []string{"healthcheck", "timeout"}, Kind: octodnsInt64, Description: "Azure healthcheck timeout"},
+			{Name: "hc_numfailures", Path: []string{"healthcheck", "numfailures"}, Kind: octodnsInt64, Description: "Azure healthcheck number of failures allowed"},
+		},
+	},
+	{
+		Key:         "cloudflare",
+		Description: "Meta config for [cloudflare provider](https://github.com/octodns/octodns-cloudflare/?tab=readme-ov-file#configuration)",
+		Fields: []octodnsField{
+			{Name: "proxied", Kind: octodnsBool, Description: "Should cloudflare proxy this record (only for A/AAAA/CNAME records)"},
+			{Name: "auto_ttl", Path: []string{"autottl"}, Kind: octodnsBool, Description: "Use cloudflare's auto-ttl *feature*, aka: set to 300"},
+		},
+	},
+	{
+		Key:         "healthcheck",
+		Description: "Healthcheck of the values of [dynamic records](https://github.com/octodns/octodns/blob/main/docs/dynamic_records.md#health-checks)",
+		Fields: []octodnsField{
+			{Name: "host", Kind: octodnsString, Description: "Host header of the healthcheck, defaults to the record fqdn"},
+			{Name: "path", Kind: octodnsString, Description: "Path of the healthcheck, defaults to /_dns"},
+			{
+				Name: "port", Kind: octodnsInt64, Description: "Port of the healthcheck, defaults to 443",
+				Int64Validators: []validator.Int64{int64validator.Between(1, 65535)},
+			},
+			{
+				Name: "protocol", Kind: octodnsString, Description: "Protocol of the healthcheck: HTTP, HTTPS or TCP, defaults to HTTPS",
+				StringValidators: []validator.String{stringvalidator.OneOf("HTTP", "HTTPS", "TCP")},
+			},
+		},
+	},
+	{
+		Key:         "ns1",
+		Description: "Healthcheck configuration for [NS1 provider](https://github.com/octodns/octodns-ns1/?tab=readme-ov-file#health-check-options)",
+		Fields: []octodnsField{
+			{Name: "hc_connect_timeout", Path: []string{"healthcheck", "connect_timeout"}, Kind: octodnsInt64, Description: "NS1 healthcheck connect timeout in seconds"},
+			{Name: "hc_response_timeout", Path: []string{"healthcheck", "response_timeout"}, Kind: octodnsInt64, Description: "NS1 healthcheck response timeout in seconds"},
+			{Name: "hc_frequency", Path: []string{"healthcheck", "frequency"}, Kind: octodnsInt64, Description: "NS1 healthcheck frequency in seconds"},
+			{
+				Name: "hc_policy", Path: []string{"healthcheck", "policy"}, Kind: octodnsString, Description: "NS1 healthcheck policy: all, one or quorum",
+				StringValidators: []validator.String{stringvalidator.OneOf("all", "one", "quorum")},
+			},
+			{Name: "hc_rapid_recheck", Path: []string{"healthcheck", "rapid_recheck"}, Kind: octodnsBool, Description: "Recheck a failed NS1 healthcheck before marking the value down"},
+		},
+	},
+	{
+		Key:         "route53",
+		Description: "Healthcheck configuration for [Route53 provider](https://github.com/octodns/octodns-route53/?tab=readme-ov-file#health-check-options)",
+		Fields: []octodnsField{
+			{Name: "hc_measure_latency", Path: []string{"healthcheck", "measure_latency"}, Kind: octodnsBool, Description: "Route53 healthcheck measures latency"},
+			{
+				Name: "hc_request_interval", Path: []string{"healthcheck", "request_interval"}, Kind: octodnsInt64, Description: "Route53 healthcheck request interval: 10 or 30 seconds",
+				Int64Validators: []validator.Int64{int64validator.OneOf(10, 30)},
+			},
+		},
+	},
+}
+
+const octodnsRawDescription = "Config of providers without an attribute above as JSON by provider key, eq: `{ ns2 = jsonencode({ ... }) }`"
+
+func (f octodnsField) path() []string {
+	if f.Path == nil {
+		return []string{f.Name}
+	}
+	return f.Path
+}
+
+func (f octodnsField) attrType() attr.Type {
+	switch f.Kind {
+	case octodnsBool:
+		return types.BoolType
+	case octodnsInt64:
+		return types.Int64Type
+	default:
+		return types.StringType
+	}
+}
+
+func (b octodnsBlock) attributeTypes() map[string]attr.Type {
+	attributes := make(map[string]attr.Type)
+	for _, field := range b.Fields {
+		attributes[field.Name] = field.attrType()
+	}
+	return attributes
+}
+
+func isOctodnsBlock(key string) bool {
+	return slices.ContainsFunc(octodnsBlocks, func(b octodnsBlock) bool { return b.Key == key })
+}
+
+// isOctodnsManaged reports if the key of the octodns config has its own
+// attribute, as a block or a lifecycle flag of the record.
+func isOctodnsManaged(key string) bool {
+	return isOctodnsBlock(key) || slices.Contains(models.OCTODNS_LIFECYCLE_KEYS, key)
+}
+
+// OctodnsAttributeTypes returns the attribute types of the octodns meta
+// config object.
+func OctodnsAttributeTypes() map[string]attr.Type {
+	attributes := map[string]attr.Type{
+		"raw": types.MapType{ElemType: types.StringType},
+	}
+	for _, block := range octodnsBlocks {
+		attributes[block.Key] = types.ObjectType{AttrTypes: block.attributeTypes()}
+	}
+	return attributes
+}
+
+// octodnsAttribute returns the `octodns` attribute of the record resources.
+func octodnsAttribute() schema.SingleNestedAttribute {
+	attributes := map[string]schema.Attribute{
+		"raw": schema.MapAttribute{
+			MarkdownDescription: octodnsRawDescription + ". Only the keys set here are managed, other keys are left as they are",
+			ElementType:         types.StringType,
+			Optional:            true,
+			Validators:          []validator.Map{octodnsRawValidator{}},
+		},
+	}
+	for _, block := range octodnsBlocks {
+		fields := map[string]schema.Attribute{}
+		for _, field := range block.Fields {
+			switch field.Kind {
+			case octodnsBool:
+				fields[field.Name] = schema.BoolAttribute{
+					MarkdownDescription: field.Description,
+					Optional:            true,
+				}
+			case octodnsInt64:
+				fields[field.Name] = schema.Int64Attribute{
+					MarkdownDescription: field.Description,
+					Optional:            true,
+					Validators:          field.Int64Validators,
+				}
+			default:
+				fields[field.Name] = schema.StringAttribute{
+					MarkdownDescription: field.Description,
+					Optional:            true,
+					Validators:          field.StringValidators,
+				}
+			}
+		}
+		attributes[block.Key] = schema.SingleNestedAttribute{
+			MarkdownDescription: block.Description,
+			Optional:            true,
+			Attributes:          fields,
+		}
+	}
+
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Additional provider specific record meta config.",
+		Optional:            true,
+		Attributes:          attributes,
+	}
+}
+
+// octodnsRawValidator checks the raw config is valid JSON and doesn't hold
+// keys that have their own attribute.
+type octodnsRawValidator struct{}
+
+func (v octodnsRawValidator) Description(_ context.Context) string {
+	return "validates raw octodns config"
+}
+
+func (v octodnsRawValidator) MarkdownDescription(_ context.Context) string {
+	return v.Description(context.Background())
+}
+
+func (v octodnsRawValidator) ValidateMap(ctx context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	for key, elem := range req.ConfigValue.Elements() {
+		if isOctodnsBlock(key) {
+			resp.Diagnostics.AddAttributeError(req.Path.AtMapKey(key), "Value Error",
+				fmt.Sprintf("%s has its own attribute, use octodns.%s instead of raw", key, key))
+			continue
+		}
+		if isOctodnsManaged(key) {
+			resp.Diagnostics.AddAttributeError(req.Path.AtMapKey(key), "Value Error",
+				fmt.Sprintf("%s has its own attribute, use the %s attribute of the record instead of raw", key, key))
+			continue
+		}
+		strVal, ok := elem.(types.String)
+		if !ok || strVal.IsNull() || strVal.IsUnknown() {
+			continue
+		}
+		if !json.Valid([]byte(strVal.ValueString())) {
+			resp.Diagnostics.AddAttributeError(req.Path.AtMapKey(key), "Value Error",
+				fmt.Sprintf("Invalid config of %s, should be JSON, eq: jsonencode({ ... })", key))
+		}
+	}
+}
+
+// OctodnsToDataModel returns the octodns meta config object of the record.
+// Keys without a block are returned in raw, limited to the keys of managed
+// unless it is nil. Raw values equal to the managed JSON keep its formatting.
+func OctodnsToDataModel(config models.OctodnsRecordConfig, managed *types.Map) (types.Object, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	attributes := map[string]attr.Value{}
+	isNull := true
+
+	for _, block := range octodnsBlocks {
+		attrTypes := block.attributeTypes()
+		value, ok, err := config.Get(block.Key)
+		if err != nil {
+			diags.AddError("Yaml Error", err.Error())
+		}
+		if !ok || err != nil {
+			attributes[block.Key] = types.ObjectNull(attrTypes)
+			continue
+		}
+
+		mapping, _ := value.(map[string]interface{})
+		fields := map[string]attr.Value{}
+		for _, field := range block.Fields {
+			var d diag.Diagnostics
+			fields[field.Name], d = octodnsFieldToDataModel(block, field, mapping)
+			diags.Append(d...)
+		}
+
+		obj, d := types.ObjectValue(attrTypes, fields)
+		diags.Append(d...)
+		attributes[block.Key] = obj
+		isNull = false
+	}
+
+	raw := map[string]attr.Value{}
+	for _, key := range config.Keys() {
+		if isOctodnsManaged(key) {
+			continue
+		}
+		var prior types.String
+		if managed != nil {
+			elem, ok := managed.Elements()[key]
+			if !ok {
+				continue
+			}
+			prior, _ = elem.(types.String)
+		}
+
+		value, _, err := config.Get(key)
+		if err != nil {
+			diags.AddError("Yaml Error", err.Error())
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			diags.AddError("Yaml Error", fmt.Sprintf("octodns.%s can't be encoded as JSON: %s", key, err))
+			continue
+		}
+		if !prior.IsNull() && !prior.IsUnknown() && jsonEqual(prior.ValueString(), encoded) {
+			raw[key] = prior
+		} else {
+			raw[key] = types.StringValue(string(encoded))
+		}
+	}
+
+	if (managed == nil && len(raw) == 0) || (managed != nil && managed.IsNull()) {
+		attributes["raw"] = types.MapNull(types.StringType)
+	} else {
+		var d diag.Diagnostics
+		attributes["raw"], d = types.MapValue(types.StringType, raw)
+		diags.Append(d...)
+		isNull = false
+	}
+
+	if isNull {
+		return types.ObjectNull(OctodnsAttributeTypes()), diags
+	}
+	obj, d := types.ObjectValue(OctodnsAttributeTypes(), attributes)
+	diags.Append(d...)
+	return obj, diags
+}
+
+func octodnsFieldToDataModel(block octodnsBlock, field octodnsField, mapping map[string]interface{}) (attr.Value, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	name := "octodns." + block.Key + "." + strings.Join(field.path(), ".")
+
+	var value interface{}
+	found := false
+	for i, key := range field.path() {
+		value, found = mapping[key]
+		if i < len(field.path())-1 {
+			mapping, _ = value.(map[string]interface{})
+		}
+	}
+
+	switch field.Kind {
+	case octodnsBool:
+		if v, ok := value.(bool); ok {
+			return types.BoolValue(v), diags
+		} else if found && value != nil {
+			diags.AddError("Yaml Error", fmt.Sprintf("%s should be true or false", name))
+		}
+		return types.BoolNull(), diags
+	case octodnsInt64:
+		if v, ok := value.(int); ok {
+			return types.Int64Value(int64(v)), diags
+		} else if found && value != nil {
+			diags.AddError("Yaml Error", fmt.Sprintf("%s should be a number", name))
+		}
+		return types.Int64Null(), diags
+	default:
+		switch value.(type) {
+		case nil:
+			return types.StringNull(), diags
+		case map[string]interface{}, []interface{}:
+			diags.AddError("Yaml Error", fmt.Sprintf("%s should be a string", name))
+			return types.StringNull(), diags
+		}
+		return types.StringValue(fmt.Sprint(value)), diags
+	}
+}
+
+// OctodnsFromDataModel sets the octodns meta config of the record. Blocks that
+// are not set are removed, keys of a block we don't model are kept.
+func OctodnsFromDataModel(data types.Object, config *models.OctodnsRecordConfig) (diags diag.Diagnostics) {
+	if data.IsUnknown() {
+		return
+	}
+	attributes := data.Attributes()
+
+	for _, block := range octodnsBlocks {
+		obj, _ := attributes[block.Key].(types.Object)
+		if obj.IsUnknown() {
+			continue
+		}
+		if obj.IsNull() {
+			config.Delete(block.Key)
+			continue
+		}
+
+		current, _, err := config.Get(block.Key)
+		if err != nil {
+			diags.AddError("Yaml Error", err.Error())
+			continue
+		}
+		mapping, ok := current.(map[string]interface{})
+		if !ok {
+			mapping = map[string]interface{}{}
+		}
+
+		fields := obj.Attributes()
+		for _, field := range block.Fields {
+			value := fields[field.Name]
+			if value == nil || value.IsUnknown() {
+				continue
+			}
+			if value.IsNull() {
+				deleteOctodnsPath(mapping, field.path())
+				continue
+			}
+			switch v := value.(type) {
+			case types.Bool:
+				setOctodnsPath(mapping, field.path(), v.ValueBool())
+			case types.Int64:
+				setOctodnsPath(mapping, field.path(), int(v.ValueInt64()))
+			case types.String:
+				setOctodnsPath(mapping, field.path(), v.ValueString())
+			}
+		}
+
+		if err = config.Set(block.Key, mapping); err != nil {
+			diags.AddError("Yaml Error", err.Error())
+		}
+	}
+
+	raw, _ := attributes["raw"].(types.Map)
+	for key, elem := range raw.Elements() {
+		strVal, ok := elem.(types.String)
+		if !ok || strVal.IsNull() || strVal.IsUnknown() {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(strVal.ValueString()), &value); err != nil {
+			diags.AddError("Value Error", fmt.Sprintf("Invalid config of %s, should be JSON: %s", key, err))
+			continue
+		}
+		if err := config.Set(key, value); err != nil {
+			diags.AddError("Yaml Error", err.Error())
+		}
+	}
+
+	return
+}
+
+// OctodnsRemoveRaw removes the keys of the raw config in data from the octodns
+// meta config of the record, used to drop keys that are no longer managed.
+func OctodnsRemoveRaw(data types.Object, config *models.OctodnsRecordConfig) {
+	if data.IsNull() || data.IsUnknown() {
+		return
+	}
+	raw, _ := data.Attributes()["raw"].(types.Map)
+	for key := range raw.Elements() {
+		if !isOctodnsManaged(key) {
+			config.Delete(key)
+		}
+	}
+}
+
+// OctodnsRawFromDataModel returns the raw config of the octodns meta config
+// object, null when it isn't set.
+func OctodnsRawFromDataModel(data types.Object) types.Map {
+	if data.IsNull() || data.IsUnknown() {
+		return types.MapNull(types.StringType)
+	}
+	raw, ok := data.Attributes()["raw"].(types.Map)
+	if !ok || raw.IsUnknown() {
+		return types.MapNull(types.StringType)
+	}
+	return raw
+}
+
+func setOctodnsPath(mapping map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := mapping[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			mapping[key] = next
+		}
+		mapping = next
+	}
+	mapping[path[len(path)-1]] = value
+}
+
+// deleteOctodnsPath deletes the value at path, mappings on the way that end
+// up empty are deleted as well.
+func deleteOctodnsPath(mapping map[string]interface{}, path []string) {
+	if len(path) > 1 {
+		next, ok := mapping[path[0]].(map[string]interface{})
+		if !ok {
+			return
+		}
+		deleteOctodnsPath(next, path[1:])
+		if len(next) > 0 {
+			return
+		}
+	}
+	delete(mapping, path[0])
+}
+
+func jsonEqual(a string, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
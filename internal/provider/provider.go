@@ -11,10 +11,12 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/topicusonderwijs/terraform-provider-octodns/internal/models"
@@ -35,16 +37,43 @@ type OctodnsProvider struct {
 
 // OctodnsProviderModel describes the provider data model.
 type OctodnsProviderModel struct {
-	GitProvider       types.String `tfsdk:"git_provider"`
-	GithubAccessToken types.String `tfsdk:"github_access_token"`
-	GithubOrg         types.String `tfsdk:"github_org"`
-	GithubRepo        types.String `tfsdk:"github_repo"`
-	GithubRetryLimit  types.Int32  `tfsdk:"github_retry_limit"`
+	GitProvider       types.String    `tfsdk:"git_provider"`
+	GithubAccessToken types.String    `tfsdk:"github_access_token"`
+	GithubApp         *GithubAppModel `tfsdk:"github_app"`
+	GithubBaseURL     types.String    `tfsdk:"github_base_url"`
+	GithubOrg         types.String    `tfsdk:"github_org"`
+	GithubRepo        types.String    `tfsdk:"github_repo"`
+	GithubRetryLimit  types.Int32     `tfsdk:"github_retry_limit"`
+	GithubUploadURL   types.String    `tfsdk:"github_upload_url"`
+
+	GitlabAccessToken types.String `tfsdk:"gitlab_access_token"`
+	GitlabBaseURL     types.String `tfsdk:"gitlab_base_url"`
+	GitlabProject     types.String `tfsdk:"gitlab_project"`
+	GitlabRetryLimit  types.Int32  `tfsdk:"gitlab_retry_limit"`
+
+	LocalPath       types.String `tfsdk:"local_path"`
+	LocalCommit     types.Bool   `tfsdk:"local_commit"`
+	LocalRetryLimit types.Int32  `tfsdk:"local_retry_limit"`
 
 	GitBranch      types.String `tfsdk:"branch"`
 	GitAuthorName  types.String `tfsdk:"author_name"`
 	GitAuthorEmail types.String `tfsdk:"author_email"`
 
+	CommitSigningKey        types.String `tfsdk:"commit_signing_key"`
+	CommitSigningKeyFile    types.String `tfsdk:"commit_signing_key_file"`
+	CommitSigningPassphrase types.String `tfsdk:"commit_signing_passphrase"`
+	GithubVerifiedCommits   types.Bool   `tfsdk:"github_verified_commits"`
+
+	ChangeMode               types.String   `tfsdk:"change_mode"`
+	PullRequestBranchPrefix  types.String   `tfsdk:"pull_request_branch_prefix"`
+	PullRequestTitle         types.String   `tfsdk:"pull_request_title"`
+	PullRequestLabels        []types.String `tfsdk:"pull_request_labels"`
+	PullRequestReviewers     []types.String `tfsdk:"pull_request_reviewers"`
+	PullRequestTeamReviewers []types.String `tfsdk:"pull_request_team_reviewers"`
+	PullRequestDraft         types.Bool     `tfsdk:"pull_request_draft"`
+
+	ConfigPath types.String `tfsdk:"config_path"`
+
 	Scopes []struct {
 		Name   types.String `tfsdk:"name"`
 		Path   types.String `tfsdk:"path"`
@@ -52,6 +81,14 @@ type OctodnsProviderModel struct {
 	} `tfsdk:"scope"`
 }
 
+// GithubAppModel describes the github_app block of the provider.
+type GithubAppModel struct {
+	AppID          types.Int64  `tfsdk:"app_id"`
+	InstallationID types.Int64  `tfsdk:"installation_id"`
+	PrivateKey     types.String `tfsdk:"private_key"`
+	PrivateKeyFile types.String `tfsdk:"private_key_file"`
+}
+
 func (p *OctodnsProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "octodns"
 	resp.Version = p.version
@@ -60,15 +97,22 @@ func (p *OctodnsProvider) Metadata(ctx context.Context, req provider.MetadataReq
 func (p *OctodnsProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "**Warning**: This provider is still a work-in-progress so use at your own risk\n\n" +
-			"This provider allows you to modify your OctoDNS zone yaml files within a github repo,\n" +
+			"This provider allows you to modify your OctoDNS zone yaml files within a github or gitlab repo or a local directory,\n" +
 			"and can handle multiple zone directories within one git repo by defining multiple scopes\n\n" +
 			"For github authentication you can use a personal access token (PAT) or use the [Github Cli](https://cli.github.com) to provide a token.\n" +
+			"In CI you can authenticate as a GitHub App installation with the `github_app` block instead, commits are then made by the app.\n" +
 			"If you don't have `gh` in your $PATH, you can point to the executable using the GH_PATH environment variable.   \n*Example*: ```GH_PATH=/opt/homebrew/bin/gh terraform plan```\n\n" +
-			"note: This provider can only manage records within existing zone files, it **cannot** manage/create zone files or alter the OctoDNS config.\n\n" +
+			"For gitlab authentication you can use a personal, group or project access token with the `api` scope.\n\n" +
+			"With `git_provider = \"local\"` the zone files are read from and written to a local directory, like a checked out clone of your dns repo. " +
+			"Set `local_commit` to commit every change to the checked out branch, pushing the commits is left to you.\n\n" +
+			"With `change_mode = \"pull_request\"` changes are committed to a feature branch and proposed using a github pull request, " +
+			"so they can be reviewed before they are merged. Until then plans read the zone files from the feature branch of the open pull request.\n\n" +
+			"Zone files are created and removed with the `octodns_zone` resource, and added to the OctoDNS config with the `octodns_config_zone` resource when `config_path` is set. " +
+			"Everything else in the OctoDNS config is left alone.\n\n" +
 			"Also this provider does not run OctoDNS after a modification, so you need your own automation for that like the OctoDNS github action",
 		Attributes: map[string]schema.Attribute{
 			"git_provider": schema.StringAttribute{
-				MarkdownDescription: "Git provider, only accepted/supported value for now is github",
+				MarkdownDescription: "Git provider, accepted values are github, gitlab and local, defaults to github",
 				Optional:            true,
 			},
 			"github_access_token": schema.StringAttribute{
@@ -76,16 +120,53 @@ func (p *OctodnsProvider) Schema(ctx context.Context, req provider.SchemaRequest
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"github_base_url": schema.StringAttribute{
+				MarkdownDescription: "Github API url, set it to the url of your GitHub Enterprise Server like `https://github.example.com/` (`/api/v3/` is appended when missing). Defaults to " + models.DEFAULT_GITHUB_URL,
+				Optional:            true,
+			},
 			"github_org": schema.StringAttribute{
-				MarkdownDescription: "Github organisation",
-				Required:            true,
+				MarkdownDescription: "Github organisation, required when using github",
+				Optional:            true,
 			},
 			"github_repo": schema.StringAttribute{
-				MarkdownDescription: "Github repository",
-				Required:            true,
+				MarkdownDescription: "Github repository, required when using github",
+				Optional:            true,
 			},
 			"github_retry_limit": schema.Int32Attribute{
-				MarkdownDescription: "How many times to retry updating files in github",
+				MarkdownDescription: "How many times to retry updating files in github when a commit conflicts with a concurrent change or hits a rate limit, defaults to 5",
+				Optional:            true,
+			},
+			"github_upload_url": schema.StringAttribute{
+				MarkdownDescription: "Github upload url of your GitHub Enterprise Server (`/api/uploads/` is appended when missing), defaults to the host of `github_base_url`",
+				Optional:            true,
+			},
+			"gitlab_access_token": schema.StringAttribute{
+				MarkdownDescription: "Gitlab access token, if not set the environment variable `GITLAB_TOKEN` will be used",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"gitlab_base_url": schema.StringAttribute{
+				MarkdownDescription: "Gitlab API url, defaults to " + models.DEFAULT_GITLAB_URL,
+				Optional:            true,
+			},
+			"gitlab_project": schema.StringAttribute{
+				MarkdownDescription: "Gitlab project ID or full path like `group/dns`, required when using gitlab",
+				Optional:            true,
+			},
+			"gitlab_retry_limit": schema.Int32Attribute{
+				MarkdownDescription: "How many times to retry updating files in gitlab when a commit conflicts with a concurrent change or hits a rate limit, defaults to 5",
+				Optional:            true,
+			},
+			"local_path": schema.StringAttribute{
+				MarkdownDescription: "Directory the scope paths are relative to, required when using local",
+				Optional:            true,
+			},
+			"local_commit": schema.BoolAttribute{
+				MarkdownDescription: "Commit changes to the git working copy at `local_path`, which must have the configured branch checked out. Other staged changes are committed as well. Defaults to false",
+				Optional:            true,
+			},
+			"local_retry_limit": schema.Int32Attribute{
+				MarkdownDescription: "How many times to retry writing zone files when they changed on disk since they were read, defaults to 5",
 				Optional:            true,
 			},
 			"branch": schema.StringAttribute{
@@ -93,21 +174,100 @@ func (p *OctodnsProvider) Schema(ctx context.Context, req provider.SchemaRequest
 				Optional:            true,
 			},
 			"author_name": schema.StringAttribute{
-				MarkdownDescription: "The Author name used in commits, defaults to owner of github token",
+				MarkdownDescription: "The Author name used in commits, defaults to owner of github/gitlab token",
 				Optional:            true,
 			},
 			"author_email": schema.StringAttribute{
-				MarkdownDescription: "The Author email used in commits, defaults to owner of github token",
+				MarkdownDescription: "The Author email used in commits, defaults to owner of github/gitlab token",
+				Optional:            true,
+			},
+			"commit_signing_key": schema.StringAttribute{
+				MarkdownDescription: "Armored GPG private key or SSH private key to sign commits with, only supported by github. " +
+					"Requires `author_name` and `author_email`, which should match the key for github to verify the commits. Conflicts with `commit_signing_key_file`",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"commit_signing_key_file": schema.StringAttribute{
+				MarkdownDescription: "Path to the armored GPG private key or SSH private key to sign commits with. Conflicts with `commit_signing_key`",
+				Optional:            true,
+			},
+			"commit_signing_passphrase": schema.StringAttribute{
+				MarkdownDescription: "Passphrase of the commit signing key, if it is encrypted",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"github_verified_commits": schema.BoolAttribute{
+				MarkdownDescription: "Commit using the GraphQL `createCommitOnBranch` mutation, github signs these commits so they show as Verified. " +
+					"Requires the `github_app` block, the commits are authored by the app. Defaults to false",
+				Optional: true,
+			},
+			"change_mode": schema.StringAttribute{
+				MarkdownDescription: "How changes end up in the branch, accepted values are commit and pull_request, defaults to commit. pull_request is only supported by github",
+				Optional:            true,
+			},
+			"pull_request_branch_prefix": schema.StringAttribute{
+				MarkdownDescription: "Prefix of the feature branch a pull request is opened from, the target branch is appended to it. Defaults to `" + models.DEFAULT_PULL_REQUEST_BRANCH_PREFIX + "`",
+				Optional:            true,
+			},
+			"pull_request_title": schema.StringAttribute{
+				MarkdownDescription: "Title of opened pull requests, defaults to `" + models.DEFAULT_PULL_REQUEST_TITLE + "`",
+				Optional:            true,
+			},
+			"pull_request_labels": schema.ListAttribute{
+				MarkdownDescription: "Labels added to opened pull requests",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"pull_request_reviewers": schema.ListAttribute{
+				MarkdownDescription: "Users requested to review opened pull requests",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"pull_request_team_reviewers": schema.ListAttribute{
+				MarkdownDescription: "Team slugs requested to review opened pull requests",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"pull_request_draft": schema.BoolAttribute{
+				MarkdownDescription: "Open pull requests as draft, defaults to false",
+				Optional:            true,
+			},
+			"config_path": schema.StringAttribute{
+				MarkdownDescription: "The git path to the OctoDNS config file, eq: config/production.yaml. Required for the `octodns_config_zone` resource",
 				Optional:            true,
 			},
 		},
 		Blocks: map[string]schema.Block{
+			"github_app": schema.SingleNestedBlock{
+				MarkdownDescription: "Authenticate as an installation of a GitHub App instead of with a token, installation tokens are refreshed before they expire. " +
+					"The app needs read and write access to the repository contents, and to pull requests when using them",
+				Attributes: map[string]schema.Attribute{
+					"app_id": schema.Int64Attribute{
+						MarkdownDescription: "ID of the GitHub App",
+						Optional:            true,
+					},
+					"installation_id": schema.Int64Attribute{
+						MarkdownDescription: "ID of the installation of the app on the organisation or repository",
+						Optional:            true,
+					},
+					"private_key": schema.StringAttribute{
+						MarkdownDescription: "PEM encoded private key of the app. Conflicts with `private_key_file`",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"private_key_file": schema.StringAttribute{
+						MarkdownDescription: "Path to the PEM encoded private key of the app. Conflicts with `private_key`",
+						Optional:            true,
+					},
+				},
+			},
 			"scope": schema.ListNestedBlock{
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
 						"name": schema.StringAttribute{
 							Optional:            true,
-							MarkdownDescription: "Unique name of this scope, leave empty for default scope.",
+							MarkdownDescription: "Unique name of this scope, leave empty for default scope. `octodns-config` is reserved for the octoDNS config file.",
+							Validators:          scopeValidators(),
 						},
 						"path": schema.StringAttribute{
 							Required:            true,
@@ -141,34 +301,60 @@ func (p *OctodnsProvider) Configure(ctx context.Context, req provider.ConfigureR
 
 	gitprovider := "github"
 	githubToken := ""
+	var githubApp *models.GitHubApp
 	githubRetryLimit := 5
+	gitlabToken := ""
+	gitlabRetryLimit := 5
+	localRetryLimit := 5
 
-	// Configuration values are now available.
-	if data.GitProvider.IsNull() || data.GitProvider.ValueString() == "github" { /* ... */
-		gitprovider = "github"
+	if !data.GitProvider.IsNull() {
+		gitprovider = data.GitProvider.ValueString()
+	}
 
-		// First check if accesstoken is configured
-		if !data.GithubAccessToken.IsNull() {
-			githubToken = data.GithubAccessToken.ValueString()
-		}
+	// Configuration values are now available.
+	switch gitprovider {
+	case "github":
+
+		// A GitHub App takes the place of a token
+		if data.GithubApp != nil {
+			if !data.GithubAccessToken.IsNull() {
+				resp.Diagnostics.AddError(
+					"Conflicting Github API access Configuration",
+					"While configuring the provider, both the github_access_token attribute and the github_app block were found, "+
+						"only one of them can be used.",
+				)
+			}
+			githubApp, err = githubAppFromDataModel(data.GithubApp)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Invalid Github App Configuration",
+					"While configuring the provider, the github_app block is invalid: "+err.Error(),
+				)
+			}
+		} else {
+			// First check if accesstoken is configured
+			if !data.GithubAccessToken.IsNull() {
+				githubToken = data.GithubAccessToken.ValueString()
+			}
 
-		// If not check if env variable GITHUB_TOKEN is set
-		if githubToken == "" {
-			githubToken = os.Getenv("GITHUB_TOKEN")
-		}
+			// If not check if env variable GITHUB_TOKEN is set
+			if githubToken == "" {
+				githubToken = os.Getenv("GITHUB_TOKEN")
+			}
 
-		// If still no token set try GitHub CLI command
-		if githubToken == "" {
-			githubToken, err = tokenFromGhCli(ctx, "https://api.github.com/", true)
-		}
+			// If still no token set try GitHub CLI command
+			if githubToken == "" {
+				githubToken, err = tokenFromGhCli(ctx, data.GithubBaseURL.ValueString(), models.IsGitHubDotCom(data.GithubBaseURL.ValueString()))
+			}
 
-		// No more sources for a token so error
-		if err != nil || githubToken == "" {
-			resp.Diagnostics.AddError(
-				"Missing Github API access Configuration",
-				"While configuring the provider, the Github access token was not found in "+
-					"provider configuration block github_access_token attribute.",
-			)
+			// No more sources for a token so error
+			if err != nil || githubToken == "" {
+				resp.Diagnostics.AddError(
+					"Missing Github API access Configuration",
+					"While configuring the provider, the Github access token was not found in "+
+						"provider configuration block github_access_token attribute or the github_app block.",
+				)
+			}
 		}
 
 		if !data.GithubRetryLimit.IsNull() {
@@ -190,14 +376,64 @@ func (p *OctodnsProvider) Configure(ctx context.Context, req provider.ConfigureR
 			)
 		}
 
-	} else { /* ... */
+	case "gitlab":
+
+		// First check if accesstoken is configured
+		if !data.GitlabAccessToken.IsNull() {
+			gitlabToken = data.GitlabAccessToken.ValueString()
+		}
+
+		// If not check if env variable GITLAB_TOKEN is set
+		if gitlabToken == "" {
+			gitlabToken = os.Getenv("GITLAB_TOKEN")
+		}
+
+		if gitlabToken == "" {
+			resp.Diagnostics.AddError(
+				"Missing Gitlab API access Configuration",
+				"While configuring the provider, the Gitlab access token was not found in "+
+					"provider configuration block gitlab_access_token attribute or the GITLAB_TOKEN environment variable.",
+			)
+		}
+
+		if !data.GitlabRetryLimit.IsNull() {
+			gitlabRetryLimit = int(data.GitlabRetryLimit.ValueInt32())
+		}
+
+		if data.GitlabProject.IsNull() {
+			resp.Diagnostics.AddError(
+				"Missing Gitlab project Configuration",
+				"While configuring the provider, the Gitlab project was not found in "+
+					"provider configuration block gitlab_project attribute.",
+			)
+		}
+
+	case "local":
+
+		if data.LocalPath.IsNull() {
+			resp.Diagnostics.AddError(
+				"Missing local path Configuration",
+				"While configuring the provider, the local path was not found in "+
+					"provider configuration block local_path attribute.",
+			)
+		}
+
+		if !data.LocalRetryLimit.IsNull() {
+			localRetryLimit = int(data.LocalRetryLimit.ValueInt32())
+		}
+
+	default:
 		resp.Diagnostics.AddError(
 			"Unsupported Git Provider Configuration",
 			"While configuring the provider, an invalid value was found for git_provider attribute. "+
-				"Allowed values: github ",
+				"Allowed values: github, gitlab, local",
 		)
 	}
 
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if data.GitBranch.IsNull() {
 		data.GitBranch = types.StringValue("main")
 	}
@@ -205,21 +441,78 @@ func (p *OctodnsProvider) Configure(ctx context.Context, req provider.ConfigureR
 	var client models.GitClient
 
 	switch gitprovider {
+	case "local":
+		client, err = models.NewLocalClient(data.LocalPath.ValueString(), data.LocalCommit.ValueBool(), localRetryLimit)
+	case "gitlab":
+		client, err = models.NewGitLabClient(gitlabToken, data.GitlabBaseURL.ValueString(), data.GitlabProject.ValueString(), gitlabRetryLimit)
 	default:
-		client, err = models.NewGitHubClient(githubToken, data.GithubOrg.ValueString(), data.GithubRepo.ValueString(), githubRetryLimit)
+		if githubApp != nil {
+			client, err = models.NewGitHubAppClient(*githubApp, data.GithubBaseURL.ValueString(), data.GithubUploadURL.ValueString(), data.GithubOrg.ValueString(), data.GithubRepo.ValueString(), githubRetryLimit)
+			break
+		}
+		client, err = models.NewGitHubClient(githubToken, data.GithubBaseURL.ValueString(), data.GithubUploadURL.ValueString(), data.GithubOrg.ValueString(), data.GithubRepo.ValueString(), githubRetryLimit)
 	}
 
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Could not create Github client",
-			"While configuring the provider, the Github client failed to configure: "+
+			"Could not create git client",
+			"While configuring the provider, the "+gitprovider+" client failed to configure: "+
 				err.Error(),
 		)
+		return
 	}
 
 	_ = client.SetBranch(data.GitBranch.ValueString())
 	_ = client.SetAuthor(data.GitAuthorName.ValueString(), data.GitAuthorEmail.ValueString())
 
+	signing, err := commitSigningFromDataModel(&data)
+	if err == nil && signing != nil {
+		err = client.SetCommitSigning(signing)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Commit Signing Configuration",
+			"While configuring the provider, the "+gitprovider+" client failed to configure commit signing: "+
+				err.Error(),
+		)
+		return
+	}
+
+	switch data.ChangeMode.ValueString() {
+	case "", models.CHANGE_MODE_COMMIT:
+	case models.CHANGE_MODE_PULL_REQUEST:
+		err = client.SetPullRequest(&models.PullRequestOptions{
+			BranchPrefix:  data.PullRequestBranchPrefix.ValueString(),
+			Title:         data.PullRequestTitle.ValueString(),
+			Labels:        stringValues(data.PullRequestLabels),
+			Reviewers:     stringValues(data.PullRequestReviewers),
+			TeamReviewers: stringValues(data.PullRequestTeamReviewers),
+			Draft:         data.PullRequestDraft.ValueBool(),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unsupported Change Mode Configuration",
+				"While configuring the provider, the "+gitprovider+" client failed to configure pull requests: "+
+					err.Error(),
+			)
+			return
+		}
+	default:
+		resp.Diagnostics.AddError(
+			"Unsupported Change Mode Configuration",
+			"While configuring the provider, an invalid value was found for change_mode attribute. "+
+				"Allowed values: commit, pull_request",
+		)
+		return
+	}
+
+	if !data.ConfigPath.IsNull() {
+		if err = client.SetConfigFile(data.ConfigPath.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Invalid config_path", err.Error())
+			return
+		}
+	}
+
 	if len(data.Scopes) == 0 {
 		// Add scope will add the default values for "" parameters
 		_ = client.AddScope("", "", "", "")
@@ -244,19 +537,28 @@ func (p *OctodnsProvider) Resources(ctx context.Context) []func() resource.Resou
 	return []func() resource.Resource{
 		NewARecordResource,
 		NewAAAARecordResource,
+		NewALIASRecordResource,
 		NewCAARecordResource,
 		NewCNAMERecordResource,
 		NewDNAMERecordResource,
+		NewDSRecordResource,
+		NewHTTPSRecordResource,
 		NewLOCRecordResource,
 		NewMXRecordResource,
 		NewNAPTRRecordResource,
 		NewNSRecordResource,
+		NewOPENPGPKEYRecordResource,
 		NewPTRRecordResource,
 		NewSPFRecordResource,
 		NewSRVRecordResource,
 		NewSSHFPRecordResource,
+		NewSVCBRecordResource,
+		NewTLSARecordResource,
 		NewTXTRecordResource,
 		NewURLFWDRecordResource,
+		NewRecordResource,
+		NewZoneResource,
+		NewConfigZoneResource,
 	}
 }
 
@@ -264,19 +566,26 @@ func (p *OctodnsProvider) DataSources(ctx context.Context) []func() datasource.D
 	return []func() datasource.DataSource{
 		NewARecordDataSource,
 		NewAAAARecordDataSource,
+		NewALIASRecordDataSource,
 		NewCAARecordDataSource,
 		NewCNAMERecordDataSource,
 		NewDNAMERecordDataSource,
+		NewDSRecordDataSource,
+		NewHTTPSRecordDataSource,
 		NewLOCRecordDataSource,
 		NewMXRecordDataSource,
 		NewNAPTRRecordDataSource,
 		NewNSRecordDataSource,
+		NewOPENPGPKEYRecordDataSource,
 		NewPTRRecordDataSource,
 		NewSPFRecordDataSource,
 		NewSRVRecordDataSource,
 		NewSSHFPRecordDataSource,
+		NewSVCBRecordDataSource,
+		NewTLSARecordDataSource,
 		NewTXTRecordDataSource,
 		NewURLFWDRecordDataSource,
+		NewZoneDataSource,
 	}
 }
 
@@ -288,6 +597,72 @@ func New(version string) func() provider.Provider {
 	}
 }
 
+func stringValues(list []types.String) []string {
+	values := make([]string, 0, len(list))
+	for _, v := range list {
+		values = append(values, v.ValueString())
+	}
+	return values
+}
+
+// githubAppFromDataModel returns the app credentials of the github_app block,
+// reading the private key from its file when needed.
+func githubAppFromDataModel(data *GithubAppModel) (*models.GitHubApp, error) {
+	if data.AppID.IsNull() || data.InstallationID.IsNull() {
+		return nil, fmt.Errorf("app_id and installation_id are required")
+	}
+
+	app := &models.GitHubApp{
+		AppID:          data.AppID.ValueInt64(),
+		InstallationID: data.InstallationID.ValueInt64(),
+	}
+	switch {
+	case !data.PrivateKey.IsNull() && !data.PrivateKeyFile.IsNull():
+		return nil, fmt.Errorf("only one of private_key and private_key_file can be set")
+	case !data.PrivateKey.IsNull():
+		app.PrivateKey = []byte(data.PrivateKey.ValueString())
+	case !data.PrivateKeyFile.IsNull():
+		key, err := os.ReadFile(data.PrivateKeyFile.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("could not read private_key_file: %w", err)
+		}
+		app.PrivateKey = key
+	default:
+		return nil, fmt.Errorf("private_key or private_key_file is required")
+	}
+	return app, nil
+}
+
+// commitSigningFromDataModel returns how commits are signed, nil when they
+// are not.
+func commitSigningFromDataModel(data *OctodnsProviderModel) (*models.CommitSigningOptions, error) {
+	var key []byte
+	switch {
+	case !data.CommitSigningKey.IsNull() && !data.CommitSigningKeyFile.IsNull():
+		return nil, fmt.Errorf("only one of commit_signing_key and commit_signing_key_file can be set")
+	case !data.CommitSigningKey.IsNull():
+		key = []byte(data.CommitSigningKey.ValueString())
+	case !data.CommitSigningKeyFile.IsNull():
+		var err error
+		if key, err = os.ReadFile(data.CommitSigningKeyFile.ValueString()); err != nil {
+			return nil, fmt.Errorf("could not read commit_signing_key_file: %w", err)
+		}
+	}
+
+	options := &models.CommitSigningOptions{GraphQL: data.GithubVerifiedCommits.ValueBool()}
+	if key != nil {
+		signer, err := models.NewCommitSigner(key, data.CommitSigningPassphrase.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		options.Signer = signer
+	}
+	if options.Signer == nil && !options.GraphQL {
+		return nil, nil
+	}
+	return options, nil
+}
+
 // See https://github.com/integrations/terraform-provider-github/issues/1822
 func tokenFromGhCli(ctx context.Context, baseURL string, isGithubDotCom bool) (string, error) {
 	ghCliPath := os.Getenv("GH_PATH")
@@ -323,3 +698,9 @@ func tokenFromGhCli(ctx context.Context, baseURL string, isGithubDotCom bool) (s
 	tflog.Info(ctx, "Using the token from GitHub CLI")
 	return strings.TrimSpace(string(out)), nil
 }
+
+// scopeValidators reject the scope name that is reserved for the octoDNS
+// config file.
+func scopeValidators() []validator.String {
+	return []validator.String{stringvalidator.NoneOf(models.CONFIG_SCOPE)}
+}
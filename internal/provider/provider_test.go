@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/topicusonderwijs/terraform-provider-octodns/internal/models"
+)
+
+// newTestClient returns a local client with the default scope in a temporary
+// directory, and the filename of zone example.com. The zone file is only
+// written when contents is set.
+func newTestClient(t *testing.T, contents string) (models.GitClient, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "zones", "example.com.yaml")
+	if err := os.Mkdir(filepath.Dir(filename), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if contents != "" {
+		if err := os.WriteFile(filename, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client, err := models.NewLocalClient(dir, false, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.AddScope(models.DEFAULT_SCOPE, "zones", "", "yaml"); err != nil {
+		t.Fatal(err)
+	}
+	return client, filename
+}
+
+// objectValue returns an object of the type with the given attributes, the
+// other attributes are null.
+func objectValue(t *testing.T, typ tftypes.Type, attributes map[string]tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	obj, ok := typ.(tftypes.Object)
+	if !ok {
+		t.Fatalf("expected an object type, got %s", typ)
+	}
+	values := map[string]tftypes.Value{}
+	for name, attrType := range obj.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	for name, value := range attributes {
+		if _, ok := obj.AttributeTypes[name]; !ok {
+			t.Fatalf("unknown attribute %s", name)
+		}
+		values[name] = value
+	}
+	return tftypes.NewValue(obj, values)
+}
+
+// resourceSchema returns the schema of the resource and its terraform type.
+func resourceSchema(t *testing.T, r resource.Resource) (resp *resource.SchemaResponse, typ tftypes.Type) {
+	t.Helper()
+
+	resp = &resource.SchemaResponse{}
+	r.Schema(context.Background(), resource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("invalid schema: %v", resp.Diagnostics)
+	}
+	return resp, resp.Schema.Type().TerraformType(context.Background())
+}
+
+func stringList(values ...string) tftypes.Value {
+	elems := []tftypes.Value{}
+	for _, value := range values {
+		elems = append(elems, tftypes.NewValue(tftypes.String, value))
+	}
+	return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elems)
+}
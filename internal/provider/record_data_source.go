@@ -25,6 +25,9 @@ func NewARecordDataSource() datasource.DataSource {
 func NewAAAARecordDataSource() datasource.DataSource {
 	return &RecordDataSource{rtype: &models.TYPE_AAAA}
 }
+func NewALIASRecordDataSource() datasource.DataSource {
+	return &RecordDataSource{rtype: &models.TYPE_ALIAS}
+}
 func NewCAARecordDataSource() datasource.DataSource {
 	return &RecordDataSource{rtype: &models.TYPE_CAA}
 }
@@ -34,6 +37,12 @@ func NewCNAMERecordDataSource() datasource.DataSource {
 func NewDNAMERecordDataSource() datasource.DataSource {
 	return &RecordDataSource{rtype: &models.TYPE_DNAME}
 }
+func NewDSRecordDataSource() datasource.DataSource {
+	return &RecordDataSource{rtype: &models.TYPE_DS}
+}
+func NewHTTPSRecordDataSource() datasource.DataSource {
+	return &RecordDataSource{rtype: &models.TYPE_HTTPS}
+}
 func NewLOCRecordDataSource() datasource.DataSource {
 	return &RecordDataSource{rtype: &models.TYPE_LOC}
 }
@@ -46,6 +55,9 @@ func NewNAPTRRecordDataSource() datasource.DataSource {
 func NewNSRecordDataSource() datasource.DataSource {
 	return &RecordDataSource{rtype: &models.TYPE_NS}
 }
+func NewOPENPGPKEYRecordDataSource() datasource.DataSource {
+	return &RecordDataSource{rtype: &models.TYPE_OPENPGPKEY}
+}
 func NewPTRRecordDataSource() datasource.DataSource {
 	return &RecordDataSource{rtype: &models.TYPE_PTR}
 }
@@ -58,6 +70,12 @@ func NewSRVRecordDataSource() datasource.DataSource {
 func NewSSHFPRecordDataSource() datasource.DataSource {
 	return &RecordDataSource{rtype: &models.TYPE_SSHFP}
 }
+func NewSVCBRecordDataSource() datasource.DataSource {
+	return &RecordDataSource{rtype: &models.TYPE_SVCB}
+}
+func NewTLSARecordDataSource() datasource.DataSource {
+	return &RecordDataSource{rtype: &models.TYPE_TLSA}
+}
 func NewTXTRecordDataSource() datasource.DataSource {
 	return &RecordDataSource{rtype: &models.TYPE_TXT}
 }
@@ -72,7 +90,7 @@ func NewRecordDataSource() datasource.DataSource {
 // RecordDataSource defines the data source implementation.
 type RecordDataSource struct {
 	rtype  *models.RType
-	client *models.GitHubClient
+	client models.GitClient
 }
 
 func (d *RecordDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -92,6 +110,7 @@ func (d *RecordDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
 			"scope": schema.StringAttribute{
 				MarkdownDescription: "Scope of zone",
 				Optional:            true,
+				Validators:          scopeValidators(),
 			},
 			"name": schema.StringAttribute{
 				MarkdownDescription: "Record Name",
@@ -113,46 +132,59 @@ func (d *RecordDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
 				MarkdownDescription: "TTL of the record, if not set the zone's or dns server setting is used",
 				Computed:            true,
 			},
-			"octodns": schema.SingleNestedAttribute{
-				MarkdownDescription: "Additional provider specific record meta config.",
+			"octodns": octodnsDataSourceAttribute(),
+			"ignored": schema.BoolAttribute{
+				MarkdownDescription: "The record is ignored by octoDNS, it is not pushed to any provider",
 				Computed:            true,
-				Attributes: map[string]schema.Attribute{
-					"cloudflare": schema.SingleNestedAttribute{
-						Attributes: map[string]schema.Attribute{
-							"proxied": schema.BoolAttribute{
-								MarkdownDescription: "Should cloudflare proxy this record (only for A/AAAA records)",
-								Computed:            true,
-							},
-							"auto_ttl": schema.BoolAttribute{
-								MarkdownDescription: "Use cloudflare's auto-ttl *feature*, aka: set to 300",
-								Computed:            true,
-							},
-						},
-						Computed:            true,
-						MarkdownDescription: "Meta config for [cloudflare provider](https://github.com/octodns/octodns-cloudflare/?tab=readme-ov-file#configuration)",
-					},
-					"azuredns": schema.SingleNestedAttribute{
-						MarkdownDescription: "Healthcheck configuration for [Azure provider](https://github.com/octodns/octodns-azure/?tab=readme-ov-file#healthchecks)",
-						Attributes: map[string]schema.Attribute{
-							"hc_interval": schema.Int64Attribute{
-								MarkdownDescription: "Azure healthcheck interval",
-								Computed:            true,
-							},
-							"hc_timeout": schema.Int64Attribute{
-								MarkdownDescription: "Azure healthcheck timeout",
-								Computed:            true,
-							},
-							"hc_numfailures": schema.Int64Attribute{
-								MarkdownDescription: "Azure healthcheck number of failures allowed",
-								Computed:            true,
-							},
-						},
-						Computed: true,
-					},
-				},
 			},
+			"included": schema.ListAttribute{
+				MarkdownDescription: "The only providers the record is pushed to",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"excluded": schema.ListAttribute{
+				MarkdownDescription: "Providers the record is not pushed to",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// octodnsDataSourceAttribute returns the computed `octodns` attribute of the
+// record data sources.
+func octodnsDataSourceAttribute() schema.SingleNestedAttribute {
+	attributes := map[string]schema.Attribute{
+		"raw": schema.MapAttribute{
+			MarkdownDescription: octodnsRawDescription,
+			ElementType:         types.StringType,
+			Computed:            true,
 		},
 	}
+	for _, block := range octodnsBlocks {
+		fields := map[string]schema.Attribute{}
+		for _, field := range block.Fields {
+			switch field.Kind {
+			case octodnsBool:
+				fields[field.Name] = schema.BoolAttribute{MarkdownDescription: field.Description, Computed: true}
+			case octodnsInt64:
+				fields[field.Name] = schema.Int64Attribute{MarkdownDescription: field.Description, Computed: true}
+			default:
+				fields[field.Name] = schema.StringAttribute{MarkdownDescription: field.Description, Computed: true}
+			}
+		}
+		attributes[block.Key] = schema.SingleNestedAttribute{
+			MarkdownDescription: block.Description,
+			Computed:            true,
+			Attributes:          fields,
+		}
+	}
+
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Additional provider specific record meta config.",
+		Computed:            true,
+		Attributes:          attributes,
+	}
 }
 
 func (d *RecordDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
@@ -161,12 +193,12 @@ func (d *RecordDataSource) Configure(ctx context.Context, req datasource.Configu
 		return
 	}
 
-	client, ok := req.ProviderData.(*models.GitHubClient)
+	client, ok := req.ProviderData.(models.GitClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *models.GitHubClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected models.GitClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -188,7 +220,12 @@ func (d *RecordDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 
 	tflog.Trace(ctx, fmt.Sprintf("==== Trying to load %s from  %s/%s", data.Name.ValueString(), data.Scope.ValueString(), data.Zone.ValueString()))
 
-	zone, err := d.client.GetZone(data.Zone.ValueString(), data.Scope.ValueString())
+	// Reads don't take part in batching, but must not race the writes to
+	// the zone cache.
+	d.client.LockRead()
+	defer d.client.Unlock()
+
+	zone, err := d.client.GetZone(ctx, data.Zone.ValueString(), data.Scope.ValueString())
 	tflog.Trace(ctx, fmt.Sprintf("==== After Zone ==== %s", ""))
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not retrieve zone: %s", err.Error()))
@@ -7,16 +7,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/topicusonderwijs/terraform-provider-octodns/internal/models"
@@ -24,6 +30,10 @@ import (
 
 type valuesValidator struct {
 	rtype string
+	// escapeSemicolons and splitLongValues are the chunking settings of TXT
+	// and SPF values
+	escapeSemicolons bool
+	splitLongValues  bool
 }
 
 func (v valuesValidator) Description(_ context.Context) string {
@@ -43,7 +53,26 @@ func (v valuesValidator) ValidateList(ctx context.Context, req validator.ListReq
 		if !ok || strVal.IsNull() || strVal.IsUnknown() {
 			continue
 		}
-		if err := models.ValidateValueString(v.rtype, strVal.ValueString()); err != nil {
+		value := strVal.ValueString()
+		if models.SupportsChunking(v.rtype) {
+			value = chunkedValue(value, v.escapeSemicolons, v.splitLongValues)
+			if !models.IsChunked(value) && len(value) > models.TXT_CHUNK_SIZE {
+				resp.Diagnostics.AddAttributeError(
+					req.Path,
+					"Value Error",
+					fmt.Sprintf("Invalid value %q: value is %d bytes, longer than %d. Split it into quoted chunks or set split_long_values", strVal.ValueString(), len(value), models.TXT_CHUNK_SIZE),
+				)
+				continue
+			}
+			if lookups := models.SPFLookups(value); lookups > models.SPF_LOOKUP_LIMIT {
+				resp.Diagnostics.AddAttributeWarning(
+					req.Path,
+					"SPF Lookup Limit",
+					fmt.Sprintf("Value %q does %d DNS lookups, more than the limit of %d. Receivers will fail the SPF check", strVal.ValueString(), lookups, models.SPF_LOOKUP_LIMIT),
+				)
+			}
+		}
+		if err := models.ValidateValueString(v.rtype, value); err != nil {
 			resp.Diagnostics.AddAttributeError(
 				req.Path,
 				"Value Error",
@@ -53,9 +82,30 @@ func (v valuesValidator) ValidateList(ctx context.Context, req validator.ListReq
 	}
 }
 
+// chunkedValue returns a TXT or SPF value as it is written to the zone file:
+// with its semicolons escaped when escape is set, and split into quoted chunks
+// when split is set and it doesn't fit in a single character string. Values
+// that are chunked already are kept as is.
+func chunkedValue(value string, escape, split bool) string {
+	if escape {
+		value = models.EscapeSemicolons(value)
+	}
+	if split && !models.IsChunked(value) && len(value) > models.TXT_CHUNK_SIZE {
+		value = models.ChunkValue(value)
+	}
+	return value
+}
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &RecordResource{}
 var _ resource.ResourceWithImportState = &RecordResource{}
+var _ resource.ResourceWithValidateConfig = &RecordResource{}
+
+// NewRecordResource returns the octodns_record resource, which takes the
+// record type as an attribute.
+func NewRecordResource() resource.Resource {
+	return &RecordResource{}
+}
 
 func NewARecordResource() resource.Resource {
 	return &RecordResource{rtype: &models.TYPE_A}
@@ -63,6 +113,9 @@ func NewARecordResource() resource.Resource {
 func NewAAAARecordResource() resource.Resource {
 	return &RecordResource{rtype: &models.TYPE_AAAA}
 }
+func NewALIASRecordResource() resource.Resource {
+	return &RecordResource{rtype: &models.TYPE_ALIAS}
+}
 func NewCAARecordResource() resource.Resource {
 	return &RecordResource{rtype: &models.TYPE_CAA}
 }
@@ -72,6 +125,12 @@ func NewCNAMERecordResource() resource.Resource {
 func NewDNAMERecordResource() resource.Resource {
 	return &RecordResource{rtype: &models.TYPE_DNAME}
 }
+func NewDSRecordResource() resource.Resource {
+	return &RecordResource{rtype: &models.TYPE_DS}
+}
+func NewHTTPSRecordResource() resource.Resource {
+	return &RecordResource{rtype: &models.TYPE_HTTPS}
+}
 func NewLOCRecordResource() resource.Resource {
 	return &RecordResource{rtype: &models.TYPE_LOC}
 }
@@ -84,6 +143,9 @@ func NewNAPTRRecordResource() resource.Resource {
 func NewNSRecordResource() resource.Resource {
 	return &RecordResource{rtype: &models.TYPE_NS}
 }
+func NewOPENPGPKEYRecordResource() resource.Resource {
+	return &RecordResource{rtype: &models.TYPE_OPENPGPKEY}
+}
 func NewPTRRecordResource() resource.Resource {
 	return &RecordResource{rtype: &models.TYPE_PTR}
 }
@@ -96,6 +158,12 @@ func NewSRVRecordResource() resource.Resource {
 func NewSSHFPRecordResource() resource.Resource {
 	return &RecordResource{rtype: &models.TYPE_SSHFP}
 }
+func NewSVCBRecordResource() resource.Resource {
+	return &RecordResource{rtype: &models.TYPE_SVCB}
+}
+func NewTLSARecordResource() resource.Resource {
+	return &RecordResource{rtype: &models.TYPE_TLSA}
+}
 func NewTXTRecordResource() resource.Resource {
 	return &RecordResource{rtype: &models.TYPE_TXT}
 }
@@ -103,19 +171,230 @@ func NewURLFWDRecordResource() resource.Resource {
 	return &RecordResource{rtype: &models.TYPE_URLFWD}
 }
 
-// RecordResource defines the resource implementation.
+// RecordResource defines the resource implementation. Without rtype it is
+// the generic octodns_record resource.
 type RecordResource struct {
 	rtype  *models.RType
-	client *models.GitHubClient
+	client models.GitClient
 }
 
 func (r *RecordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	if r.rtype == nil {
+		resp.TypeName = req.ProviderTypeName + "_record"
+		return
+	}
 	resp.TypeName = req.ProviderTypeName + "_" + r.rtype.LowerString() + "_record"
 }
 
+// modelGetter is implemented by tfsdk.Config, tfsdk.Plan and tfsdk.State.
+type modelGetter interface {
+	Get(ctx context.Context, target interface{}) diag.Diagnostics
+}
+
+// valueFields returns the fields of the structured values, the generic
+// resource takes the fields of all types. Nil when the record type has no
+// structured values.
+func (r *RecordResource) valueFields() []models.ValueField {
+	if r.rtype == nil {
+		return models.AllValueFields()
+	}
+	return models.VALUE_FIELDS[r.rtype.String()]
+}
+
+// hasDynamic reports if the resource takes dynamic config, the generic
+// resource takes it for the record types that support it.
+func (r *RecordResource) hasDynamic() bool {
+	return r.rtype == nil || models.SupportsDynamic(r.rtype.String())
+}
+
+// hasGeo reports if the resource takes legacy geo config, the generic
+// resource takes it for the record types that support it.
+func (r *RecordResource) hasGeo() bool {
+	return r.rtype == nil || models.SupportsGeo(r.rtype.String())
+}
+
+// hasChunking reports if the resource takes the chunking settings, the
+// generic resource takes them for TXT and SPF records.
+func (r *RecordResource) hasChunking() bool {
+	return r.rtype == nil || models.SupportsChunking(r.rtype.String())
+}
+
+// chunkingSettings returns escape_semicolons and split_long_values of the
+// model, unset settings take their default.
+func chunkingSettings(data *GenericRecordModel) (escape, split bool) {
+	return data.EscapeSemicolons.ValueBool(), data.SplitLongValues.IsNull() || data.SplitLongValues.ValueBool()
+}
+
+// getModel reads the record model and returns it with the record type, which
+// is an attribute of the generic resource. Attributes the resource doesn't
+// have are null.
+func (r *RecordResource) getModel(ctx context.Context, from modelGetter) (*GenericRecordModel, string, diag.Diagnostics) {
+	if r.rtype == nil {
+		var data *GenericRecordModel
+		diags := from.Get(ctx, &data)
+		if data == nil {
+			return nil, "", diags
+		}
+		return data, data.Type.ValueString(), diags
+	}
+
+	data := &GenericRecordModel{
+		StructuredRecordModel: StructuredRecordModel{StructuredValues: types.ListNull(types.ObjectType{})},
+		Dynamic:               types.ObjectNull(DynamicAttributeTypes()),
+		Geo:                   types.MapNull(GeoElementType),
+		Type:                  types.StringValue(r.rtype.String()),
+	}
+	var diags diag.Diagnostics
+	switch {
+	case r.hasGeo():
+		var model *GeoRecordModel
+		if diags = from.Get(ctx, &model); model == nil {
+			return nil, "", diags
+		}
+		data.RecordModel, data.Dynamic, data.Geo = model.RecordModel, model.Dynamic, model.Geo
+	case r.hasDynamic():
+		var model *DynamicRecordModel
+		if diags = from.Get(ctx, &model); model == nil {
+			return nil, "", diags
+		}
+		data.RecordModel, data.Dynamic = model.RecordModel, model.Dynamic
+	case r.hasChunking():
+		var model *ChunkedRecordModel
+		if diags = from.Get(ctx, &model); model == nil {
+			return nil, "", diags
+		}
+		data.RecordModel, data.EscapeSemicolons, data.SplitLongValues = model.RecordModel, model.EscapeSemicolons, model.SplitLongValues
+	case r.valueFields() != nil:
+		var model *StructuredRecordModel
+		if diags = from.Get(ctx, &model); model == nil {
+			return nil, "", diags
+		}
+		data.StructuredRecordModel = *model
+	default:
+		var model *RecordModel
+		if diags = from.Get(ctx, &model); model == nil {
+			return nil, "", diags
+		}
+		data.RecordModel = *model
+	}
+	return data, r.rtype.String(), diags
+}
+
+// setState writes the attributes of the resource from the record model, with
+// the record type for the generic resource.
+func (r *RecordResource) setState(ctx context.Context, state *tfsdk.State, data *GenericRecordModel, rtype string) diag.Diagnostics {
+	switch {
+	case r.rtype == nil:
+		data.Type = types.StringValue(rtype)
+		return state.Set(ctx, data)
+	case r.hasGeo():
+		return state.Set(ctx, &GeoRecordModel{DynamicRecordModel: DynamicRecordModel{RecordModel: data.RecordModel, Dynamic: data.Dynamic}, Geo: data.Geo})
+	case r.hasDynamic():
+		return state.Set(ctx, &DynamicRecordModel{RecordModel: data.RecordModel, Dynamic: data.Dynamic})
+	case r.hasChunking():
+		return state.Set(ctx, &ChunkedRecordModel{RecordModel: data.RecordModel, EscapeSemicolons: data.EscapeSemicolons, SplitLongValues: data.SplitLongValues})
+	case r.valueFields() != nil:
+		return state.Set(ctx, &data.StructuredRecordModel)
+	default:
+		return state.Set(ctx, &data.RecordModel)
+	}
+}
+
+// recordFromDataModel sets the record from the model, taking the structured
+// values when they are set. TXT and SPF values are escaped and chunked as
+// configured.
+func (r *RecordResource) recordFromDataModel(ctx context.Context, data *GenericRecordModel, record *models.Record) diag.Diagnostics {
+	model := data.RecordModel
+	if r.hasChunking() && models.SupportsChunking(record.Type) {
+		escape, split := chunkingSettings(data)
+		model.Values = make([]types.String, len(data.Values))
+		for i, value := range data.Values {
+			model.Values[i] = types.StringValue(chunkedValue(value.ValueString(), escape, split))
+		}
+	}
+	diags := RecordFromDataModel(ctx, &model, record)
+	if !data.StructuredValues.IsNull() {
+		diags.Append(StructuredValuesFromDataModel(data.StructuredValues, record)...)
+	}
+	if r.hasDynamic() && models.SupportsDynamic(record.Type) {
+		dynamic, d := DynamicFromDataModel(ctx, data.Dynamic)
+		diags.Append(d...)
+		record.SetDynamic(dynamic)
+	}
+	if r.hasGeo() && models.SupportsGeo(record.Type) {
+		geo, d := GeoFromDataModel(ctx, data.Geo)
+		diags.Append(d...)
+		record.Geo = geo
+	}
+	return diags
+}
+
+// recordToDataModel sets the model from the record, as structured values when
+// the model uses them. Only the raw octodns keys that were managed already are
+// read. TXT and SPF values keep the form of the state when they are written
+// the same.
+func (r *RecordResource) recordToDataModel(ctx context.Context, data *GenericRecordModel, record *models.Record) diag.Diagnostics {
+	managed := OctodnsRawFromDataModel(data.Octodns)
+	prior := data.Values
+	diags := RecordToDataModel(ctx, &data.RecordModel, record)
+	if r.hasChunking() {
+		// Imported resources have no settings yet
+		if data.EscapeSemicolons.IsNull() {
+			data.EscapeSemicolons = types.BoolValue(false)
+		}
+		if data.SplitLongValues.IsNull() {
+			data.SplitLongValues = types.BoolValue(true)
+		}
+	}
+	if r.hasChunking() && models.SupportsChunking(record.Type) {
+		escape, split := chunkingSettings(data)
+		for i := range data.Values {
+			if i < len(prior) && chunkedValue(prior[i].ValueString(), escape, split) == data.Values[i].ValueString() {
+				data.Values[i] = prior[i]
+			}
+		}
+	}
+	var d diag.Diagnostics
+	data.Octodns, d = OctodnsToDataModel(record.Octodns, &managed)
+	diags.Append(d...)
+	if !data.StructuredValues.IsNull() {
+		data.Values = nil
+		data.StructuredValues, d = StructuredValuesToDataModel(record, r.valueFields())
+		diags.Append(d...)
+	}
+	if r.hasDynamic() {
+		data.Dynamic, d = DynamicToDataModel(ctx, record)
+		diags.Append(d...)
+	}
+	if r.hasGeo() {
+		data.Geo, d = GeoToDataModel(ctx, record)
+		diags.Append(d...)
+	}
+	return diags
+}
+
+// recordId returns the resource id, the generic resource includes the type.
+func (r *RecordResource) recordId(data *GenericRecordModel, rtype string) types.String {
+	if r.rtype == nil {
+		return types.StringValue(fmt.Sprintf("%s %s %s %s", data.Scope.ValueString(), data.Zone.ValueString(), data.Name.ValueString(), rtype))
+	}
+	return types.StringValue(fmt.Sprintf("%s %s %s", data.Scope.ValueString(), data.Zone.ValueString(), data.Name.ValueString()))
+}
+
 func (r *RecordResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	valuesValidators := []validator.List{}
+	description := "Record resource for any record type, the values are validated against the type"
+	if r.rtype != nil {
+		// The values of chunked types are validated by ValidateConfig,
+		// which knows their chunking settings
+		if !r.hasChunking() {
+			valuesValidators = append(valuesValidators, valuesValidator{rtype: r.rtype.String()})
+		}
+		description = r.rtype.String() + " record resource"
+	}
+
 	resp.Schema = schema.Schema{
-		MarkdownDescription: r.rtype.String() + " record resource",
+		MarkdownDescription: description,
 
 		Attributes: map[string]schema.Attribute{
 			"zone": schema.StringAttribute{
@@ -128,6 +407,7 @@ func (r *RecordResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			"scope": schema.StringAttribute{
 				MarkdownDescription: "Scope of zone",
 				Optional:            true,
+				Validators:          scopeValidators(),
 				Computed:            true,
 				Default:             stringdefault.StaticString(models.DEFAULT_SCOPE),
 				PlanModifiers: []planmodifier.String{
@@ -151,9 +431,7 @@ func (r *RecordResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			"values": schema.ListAttribute{
 				ElementType: types.StringType,
 				Required:    true,
-				Validators: []validator.List{
-					valuesValidator{rtype: r.rtype.String()},
-				},
+				Validators:  valuesValidators,
 			},
 			"ttl": schema.Int64Attribute{
 				Optional:            true,
@@ -161,40 +439,158 @@ func (r *RecordResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Default:             int64default.StaticInt64(3600),
 				MarkdownDescription: "TTL of the record, leave empty for zone of server defaults",
 			},
-			"octodns": schema.SingleNestedAttribute{
-				MarkdownDescription: "Additional provider specific record meta config.",
+			"octodns": octodnsAttribute(),
+			"ignored": schema.BoolAttribute{
+				MarkdownDescription: "Have octoDNS ignore the record, so it is staged in the zone file without being pushed to any provider",
 				Optional:            true,
-				Attributes: map[string]schema.Attribute{
-					"cloudflare": schema.SingleNestedAttribute{
-						Optional:            true,
-						MarkdownDescription: "Meta config for [cloudflare provider](https://github.com/octodns/octodns-cloudflare/?tab=readme-ov-file#configuration)",
-						Attributes: map[string]schema.Attribute{
-							"proxied": schema.BoolAttribute{
-								MarkdownDescription: "Should cloudflare proxy this record (only for A/AAAA/CNAME records)",
-								Optional:            true,
-							},
-							"auto_ttl": schema.BoolAttribute{
-								MarkdownDescription: "Use cloudflare's auto-ttl *feature*, aka: set to 300",
-								Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"included": schema.ListAttribute{
+				MarkdownDescription: "Only push the record to these providers, by their id in the octoDNS config",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"excluded": schema.ListAttribute{
+				MarkdownDescription: "Don't push the record to these providers, by their id in the octoDNS config",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+		},
+	}
+
+	if fields := r.valueFields(); fields != nil {
+		resp.Schema.Attributes["values"] = schema.ListAttribute{
+			MarkdownDescription: "Values as strings, with the parts of a value separated by spaces. Conflicts with `structured_values`",
+			ElementType:         types.StringType,
+			Optional:            true,
+			Validators: append(valuesValidators, listvalidator.ExactlyOneOf(
+				path.MatchRoot("values"), path.MatchRoot("structured_values"),
+			)),
+		}
+		resp.Schema.Attributes["structured_values"] = schema.ListNestedAttribute{
+			MarkdownDescription: "Values with a field per part of the value. Conflicts with `values`",
+			Optional:            true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: valueFieldAttributes(fields, r.rtype != nil),
+			},
+		}
+	}
+
+	if r.hasDynamic() {
+		resp.Schema.Attributes["dynamic"] = dynamicAttribute()
+	}
+	if r.hasGeo() {
+		resp.Schema.Attributes["geo"] = schema.MapAttribute{
+			MarkdownDescription: "Legacy geo config, only for " + strings.Join(models.GEO_TYPES, ", ") + " records. Values by geo code: a continent, " +
+				"optionally followed by a country and province, eq: `AF`, `NA-US` or `NA-US-CA`",
+			ElementType: GeoElementType,
+			Optional:    true,
+		}
+	}
+
+	if r.hasChunking() {
+		only := ""
+		if r.rtype == nil {
+			only = ", only for " + strings.Join(models.CHUNKED_TYPES, ", ") + " records"
+		}
+		resp.Schema.Attributes["escape_semicolons"] = schema.BoolAttribute{
+			MarkdownDescription: "Escape the semicolons of the values, which octoDNS requires" + only,
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(false),
+		}
+		resp.Schema.Attributes["split_long_values"] = schema.BoolAttribute{
+			MarkdownDescription: fmt.Sprintf("Split values longer than %d bytes into quoted chunks, when disabled these values are invalid%s", models.TXT_CHUNK_SIZE, only),
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(true),
+		}
+	}
+
+	if r.rtype == nil {
+		resp.Schema.Attributes["type"] = schema.StringAttribute{
+			MarkdownDescription: "Record type, one of " + strings.Join(enabledTypes(), ", "),
+			Required:            true,
+			Validators: []validator.String{
+				stringvalidator.OneOf(enabledTypes()...),
+			},
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		}
+	}
+}
+
+// dynamicAttribute returns the schema attribute of the dynamic config.
+func dynamicAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Dynamic config, only for " + strings.Join(models.DYNAMIC_TYPES, ", ") + " records. Rules pick a pool of values by geo or subnet, " +
+			"pools fall back to another pool and in the end to `values` when their values are down. " +
+			"See [dynamic records](https://github.com/octodns/octodns/blob/main/docs/dynamic_records.md)",
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"pools": schema.MapNestedAttribute{
+				MarkdownDescription: "Pools of values by name",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"fallback": schema.StringAttribute{
+							MarkdownDescription: "Pool to use when all values of this pool are down",
+							Optional:            true,
+						},
+						"values": schema.ListNestedAttribute{
+							MarkdownDescription: "Values of the pool",
+							Required:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"value": schema.StringAttribute{
+										MarkdownDescription: "Value, like the values of the record",
+										Required:            true,
+									},
+									"weight": schema.Int64Attribute{
+										MarkdownDescription: "Weight of the value within the pool, 1-100",
+										Optional:            true,
+									},
+									"status": schema.StringAttribute{
+										MarkdownDescription: "Health of the value: up, down or obey the healthcheck",
+										Optional:            true,
+										Validators: []validator.String{
+											stringvalidator.OneOf("up", "down", "obey"),
+										},
+									},
+								},
 							},
 						},
 					},
-					"azuredns": schema.SingleNestedAttribute{
-						Optional:            true,
-						MarkdownDescription: "Healthcheck configuration for [Azure provider](https://github.com/octodns/octodns-azure/?tab=readme-ov-file#healthchecks)",
-						Attributes: map[string]schema.Attribute{
-							"hc_interval": schema.Int64Attribute{
-								MarkdownDescription: "Azure healthcheck interval",
-								Optional:            true,
-							},
-							"hc_timeout": schema.Int64Attribute{
-								MarkdownDescription: "Azure healthcheck timeout",
-								Optional:            true,
-							},
-							"hc_numfailures": schema.Int64Attribute{
-								MarkdownDescription: "Azure healthcheck number of failures allowed",
-								Optional:            true,
-							},
+				},
+			},
+			"rules": schema.ListNestedAttribute{
+				MarkdownDescription: "Rules picking a pool, in order. Only the last rule may have no geos or subnets",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"pool": schema.StringAttribute{
+							MarkdownDescription: "Pool to use",
+							Required:            true,
+						},
+						"geos": schema.ListAttribute{
+							MarkdownDescription: "Geos matched by the rule, eq: NA-US-CA",
+							ElementType:         types.StringType,
+							Optional:            true,
+						},
+						"subnets": schema.ListAttribute{
+							MarkdownDescription: "Subnets matched by the rule, eq: 10.0.0.0/8",
+							ElementType:         types.StringType,
+							Optional:            true,
 						},
 					},
 				},
@@ -203,6 +599,160 @@ func (r *RecordResource) Schema(ctx context.Context, req resource.SchemaRequest,
 	}
 }
 
+// valueFieldAttributes returns the schema attributes of a structured value,
+// fields are only required when required is set.
+func valueFieldAttributes(fields []models.ValueField, required bool) map[string]schema.Attribute {
+	attributes := map[string]schema.Attribute{}
+	for _, field := range fields {
+		isRequired := required && field.Required
+		switch field.Kind {
+		case models.VALUE_FIELD_INT:
+			attributes[field.Name] = schema.Int64Attribute{MarkdownDescription: field.Description, Required: isRequired, Optional: !isRequired}
+		case models.VALUE_FIELD_FLOAT:
+			attributes[field.Name] = schema.Float64Attribute{MarkdownDescription: field.Description, Required: isRequired, Optional: !isRequired}
+		default:
+			attributes[field.Name] = schema.StringAttribute{MarkdownDescription: field.Description, Required: isRequired, Optional: !isRequired}
+		}
+	}
+	return attributes
+}
+
+// enabledTypes returns the names of the enabled record types, sorted.
+func enabledTypes() []string {
+	names := []string{}
+	for name, rtype := range models.TYPES {
+		if rtype.IsEnabled() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateConfig validates the values against the type of the generic
+// resource and of the chunked types, the other typed resources use
+// valuesValidator. Structured values and dynamic config are validated for all
+// resources.
+func (r *RecordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	rtype := types.StringNull()
+	if r.rtype != nil {
+		rtype = types.StringValue(r.rtype.String())
+	} else {
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &rtype)...)
+		if resp.Diagnostics.HasError() || rtype.IsNull() || rtype.IsUnknown() {
+			return
+		}
+	}
+
+	if r.hasChunking() {
+		resp.Diagnostics.Append(r.validateValues(ctx, req.Config, rtype.ValueString())...)
+	}
+
+	if r.valueFields() != nil {
+		resp.Diagnostics.Append(r.validateStructuredValues(ctx, req.Config, rtype.ValueString())...)
+	}
+	if r.hasDynamic() {
+		resp.Diagnostics.Append(r.validateDynamic(ctx, req.Config, rtype.ValueString())...)
+	}
+	if r.hasGeo() {
+		resp.Diagnostics.Append(r.validateGeo(ctx, req.Config, rtype.ValueString())...)
+	}
+}
+
+// validateValues validates the values with the chunking settings, once these
+// are known.
+func (r *RecordResource) validateValues(ctx context.Context, config tfsdk.Config, rtype string) (diags diag.Diagnostics) {
+	var values types.List
+	diags.Append(config.GetAttribute(ctx, path.Root("values"), &values)...)
+	if diags.HasError() {
+		return
+	}
+
+	v := valuesValidator{rtype: rtype, splitLongValues: true}
+	if models.SupportsChunking(rtype) {
+		var escape, split types.Bool
+		diags.Append(config.GetAttribute(ctx, path.Root("escape_semicolons"), &escape)...)
+		diags.Append(config.GetAttribute(ctx, path.Root("split_long_values"), &split)...)
+		if diags.HasError() || escape.IsUnknown() || split.IsUnknown() {
+			return
+		}
+		v.escapeSemicolons = escape.ValueBool()
+		v.splitLongValues = split.IsNull() || split.ValueBool()
+	}
+
+	listResp := &validator.ListResponse{}
+	v.ValidateList(ctx, validator.ListRequest{Path: path.Root("values"), ConfigValue: values}, listResp)
+	diags.Append(listResp.Diagnostics...)
+	return
+}
+
+func (r *RecordResource) validateStructuredValues(ctx context.Context, config tfsdk.Config, rtype string) (diags diag.Diagnostics) {
+	var structured types.List
+	diags.Append(config.GetAttribute(ctx, path.Root("structured_values"), &structured)...)
+	if diags.HasError() || structured.IsNull() || structured.IsUnknown() {
+		return
+	}
+
+	for i, elem := range structured.Elements() {
+		fields, known := StructuredValueFields(elem)
+		if !known {
+			continue
+		}
+		if err := models.ValidateValueFields(rtype, fields); err != nil {
+			diags.AddAttributeError(
+				path.Root("structured_values").AtListIndex(i),
+				"Value Error",
+				fmt.Sprintf("Invalid %s value: %s", rtype, err),
+			)
+		}
+	}
+	return
+}
+
+// validateGeo validates the geo config once all of it is known.
+func (r *RecordResource) validateGeo(ctx context.Context, config tfsdk.Config, rtype string) (diags diag.Diagnostics) {
+	var geo types.Map
+	diags.Append(config.GetAttribute(ctx, path.Root("geo"), &geo)...)
+	if diags.HasError() || geo.IsNull() {
+		return
+	}
+	if value, err := geo.ToTerraformValue(ctx); err != nil || !value.IsFullyKnown() {
+		return
+	}
+
+	values, d := GeoFromDataModel(ctx, geo)
+	diags.Append(d...)
+	if diags.HasError() {
+		return
+	}
+	if err := models.ValidateGeo(rtype, values); err != nil {
+		diags.AddAttributeError(path.Root("geo"), "Geo Error", fmt.Sprintf("Invalid geo config: %s", err))
+	}
+	return
+}
+
+// validateDynamic validates the dynamic config once all of it is known.
+func (r *RecordResource) validateDynamic(ctx context.Context, config tfsdk.Config, rtype string) (diags diag.Diagnostics) {
+	var obj types.Object
+	diags.Append(config.GetAttribute(ctx, path.Root("dynamic"), &obj)...)
+	if diags.HasError() || obj.IsNull() {
+		return
+	}
+	if value, err := obj.ToTerraformValue(ctx); err != nil || !value.IsFullyKnown() {
+		return
+	}
+
+	dynamic, d := DynamicFromDataModel(ctx, obj)
+	diags.Append(d...)
+	if diags.HasError() {
+		return
+	}
+	if err := dynamic.Validate(rtype); err != nil {
+		diags.AddAttributeError(path.Root("dynamic"), "Dynamic Error", fmt.Sprintf("Invalid dynamic config: %s", err))
+	}
+	return
+}
+
 func (r *RecordResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -210,11 +760,11 @@ func (r *RecordResource) Configure(ctx context.Context, req resource.ConfigureRe
 
 	tflog.Trace(ctx, "- Resource Configure")
 
-	client, ok := req.ProviderData.(*models.GitHubClient)
+	client, ok := req.ProviderData.(models.GitClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected models.GitClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -224,22 +774,21 @@ func (r *RecordResource) Configure(ctx context.Context, req resource.ConfigureRe
 
 func (r *RecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	tflog.Trace(ctx, "- Resource Create")
-	var data *RecordModel
 
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	data, rtype, diags := r.getModel(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Add(+1) BEFORE Lock so all queued goroutines are counted.
-	// FlushIfLast owns the Add(-1) — do NOT defer it separately.
-	r.client.InFlight.Add(1)
-	r.client.Mutex.Lock()
-	defer r.client.Mutex.Unlock()
+	// Lock does the InFlight.Add(+1) BEFORE locking so all queued goroutines
+	// are counted. FlushIfLast owns the Add(-1) — do NOT defer it separately.
+	r.client.Lock()
+	defer r.client.Unlock()
 
-	zone, err := r.client.GetZone(data.Zone.ValueString(), data.Scope.ValueString())
+	zone, err := r.client.GetZone(ctx, data.Zone.ValueString(), data.Scope.ValueString())
 	if err != nil {
-		_ = r.client.FlushIfLast()
+		_ = r.client.FlushIfLast(ctx)
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not retrieve zone: %s", err.Error()))
 		return
 	}
@@ -248,7 +797,7 @@ func (r *RecordResource) Create(ctx context.Context, req resource.CreateRequest,
 	subdomain, err := zone.CreateSubdomain(data.Name.ValueString())
 	if err != nil {
 		if !errors.Is(err, models.ErrSubdomainAlreadyExists) {
-			_ = r.client.FlushIfLast()
+			_ = r.client.FlushIfLast(ctx)
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create subdomain, got error: %s", err))
 			return
 		}
@@ -260,59 +809,59 @@ func (r *RecordResource) Create(ctx context.Context, req resource.CreateRequest,
 		if subdomainCreated {
 			_ = zone.DeleteSubdomain(subdomain.Name)
 		} else {
-			_ = subdomain.DeleteType(r.rtype.String())
+			_ = subdomain.DeleteType(rtype)
 		}
 	}
 
-	record, err := subdomain.CreateType(r.rtype.String())
+	record, err := subdomain.CreateType(rtype)
 	if err != nil {
 		if subdomainCreated {
 			_ = zone.DeleteSubdomain(subdomain.Name)
 		}
-		_ = r.client.FlushIfLast()
+		_ = r.client.FlushIfLast(ctx)
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create type record, got error: %s", err))
 		return
 	}
 
-	resp.Diagnostics.Append(RecordFromDataModel(ctx, data, record)...)
+	resp.Diagnostics.Append(r.recordFromDataModel(ctx, data, record)...)
 	if resp.Diagnostics.HasError() {
 		rollback()
-		_ = r.client.FlushIfLast()
+		_ = r.client.FlushIfLast(ctx)
 		return
 	}
 
 	err = subdomain.UpdateYaml()
 	if err != nil {
 		rollback()
-		_ = r.client.FlushIfLast()
+		_ = r.client.FlushIfLast(ctx)
 		resp.Diagnostics.AddError("Yaml Error", fmt.Sprintf("Unable to update subdomain in yaml, got error: %s", err))
 		return
 	}
 
-	r.client.MarkZoneDirty(zone, fmt.Sprintf("chore(%s/%s): create %s record for %s", data.Scope.ValueString(), data.Zone.ValueString(), r.rtype.String(), data.Name.ValueString()))
+	r.client.MarkZoneDirty(ctx, zone, fmt.Sprintf("chore(%s/%s): create %s record for %s", data.Scope.ValueString(), data.Zone.ValueString(), rtype, data.Name.ValueString()), models.NewRecordUpsert(data.Name.ValueString(), record))
 
 	// FlushIfLast does the InFlight.Add(-1) internally.
-	if err = r.client.FlushIfLast(); err != nil {
+	if err = r.client.FlushIfLast(ctx); err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not save zone: %s", err.Error()))
 		return
 	}
 
-	data.Id = types.StringValue(fmt.Sprintf("%s %s %s", data.Scope.ValueString(), data.Zone.ValueString(), data.Name.ValueString()))
+	data.Id = r.recordId(data, rtype)
 	tflog.Trace(ctx, "created a resource")
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(r.setState(ctx, &resp.State, data, rtype)...)
 }
 
 func (r *RecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var data *RecordModel
 	tflog.Trace(ctx, "- Resource Read")
 
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	data, rtype, diags := r.getModel(ctx, req.State)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	parts := strings.Split(data.Id.ValueString(), " ")
-	if len(parts) != 3 {
+	if (r.rtype != nil && len(parts) != 3) || (r.rtype == nil && len(parts) != 4) {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Malformed ID: %s", data.Id.String()))
 		return
 	}
@@ -320,10 +869,18 @@ func (r *RecordResource) Read(ctx context.Context, req resource.ReadRequest, res
 	data.Scope = types.StringValue(parts[0])
 	data.Zone = types.StringValue(parts[1])
 	data.Name = types.StringValue(parts[2])
+	if r.rtype == nil {
+		rtype = strings.ToUpper(parts[3])
+	}
 
 	tflog.Trace(ctx, fmt.Sprintf("==== Trying to load %s from  %s/%s", data.Name.ValueString(), data.Scope.ValueString(), data.Zone.ValueString()))
 
-	zone, err := r.client.GetZone(data.Zone.ValueString(), data.Scope.ValueString())
+	// Reads don't take part in batching, but must not race the writes to
+	// the zone cache.
+	r.client.LockRead()
+	defer r.client.Unlock()
+
+	zone, err := r.client.GetZone(ctx, data.Zone.ValueString(), data.Scope.ValueString())
 	tflog.Trace(ctx, fmt.Sprintf("==== After Zone ==== %s", ""))
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Retreiving zone %s from scope %s resulted in error: %s", data.Zone.ValueString(), data.Scope.ValueString(), err.Error()))
@@ -336,54 +893,53 @@ func (r *RecordResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	record, err := subdomain.GetType(r.rtype.String())
+	record, err := subdomain.GetType(rtype)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read type %s, got error: %s", r.rtype.String(), err))
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read type %s, got error: %s", rtype, err))
 		return
 	}
 
-	resp.Diagnostics.Append(RecordToDataModel(ctx, data, record)...)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(r.recordToDataModel(ctx, data, record)...)
+	resp.Diagnostics.Append(r.setState(ctx, &resp.State, data, rtype)...)
 }
 
 func (r *RecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data *RecordModel
-	var state *RecordModel
 	tflog.Trace(ctx, "- Resource Update")
 
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	data, rtype, diags := r.getModel(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	state, _, diags := r.getModel(ctx, req.State)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Add(+1) BEFORE Lock so all queued goroutines are counted.
-	// FlushIfLast owns the Add(-1) — do NOT defer it separately.
-	r.client.InFlight.Add(1)
-	r.client.Mutex.Lock()
-	defer r.client.Mutex.Unlock()
+	// Lock does the InFlight.Add(+1) BEFORE locking so all queued goroutines
+	// are counted. FlushIfLast owns the Add(-1) — do NOT defer it separately.
+	r.client.Lock()
+	defer r.client.Unlock()
 
-	zone, err := r.client.GetZone(state.Zone.ValueString(), state.Scope.ValueString())
+	zone, err := r.client.GetZone(ctx, state.Zone.ValueString(), state.Scope.ValueString())
 	if err != nil {
-		_ = r.client.FlushIfLast()
+		_ = r.client.FlushIfLast(ctx)
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not retrieve zone: %s", err.Error()))
 		return
 	}
 
 	subdomain, err := zone.FindSubdomain(state.Name.ValueString())
 	if err != nil {
-		_ = r.client.FlushIfLast()
+		_ = r.client.FlushIfLast(ctx)
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find subdomain, got error: %s", err))
 		return
 	}
 
-	record, err := subdomain.GetType(r.rtype.String())
+	record, err := subdomain.GetType(rtype)
 	if err != nil {
-		_ = r.client.FlushIfLast()
+		_ = r.client.FlushIfLast(ctx)
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find type record, got error: %s", err))
 		return
 	}
@@ -392,76 +948,82 @@ func (r *RecordResource) Update(ctx context.Context, req resource.UpdateRequest,
 	copy(oldValues, record.Values)
 	oldTTL := record.TTL
 	oldOctodns := record.Octodns
+	oldDynamic := record.Dynamic
+	oldGeo := record.Geo
+	oldIgnored, oldIncluded, oldExcluded := record.Ignored, record.Included, record.Excluded
 
 	restore := func() {
 		record.Values = oldValues
 		record.TTL = oldTTL
 		record.Octodns = oldOctodns
+		record.Dynamic = oldDynamic
+		record.Geo = oldGeo
+		record.Ignored, record.Included, record.Excluded = oldIgnored, oldIncluded, oldExcluded
 		_ = subdomain.UpdateYaml()
 	}
 
-	resp.Diagnostics.Append(RecordFromDataModel(ctx, data, record)...)
+	// Raw octodns keys dropped from the plan are no longer managed
+	OctodnsRemoveRaw(state.Octodns, &record.Octodns)
+	resp.Diagnostics.Append(r.recordFromDataModel(ctx, data, record)...)
 	if resp.Diagnostics.HasError() {
 		restore()
-		_ = r.client.FlushIfLast()
+		_ = r.client.FlushIfLast(ctx)
 		return
 	}
 
 	err = subdomain.UpdateYaml()
 	if err != nil {
 		restore()
-		_ = r.client.FlushIfLast()
+		_ = r.client.FlushIfLast(ctx)
 		resp.Diagnostics.AddError("Yaml Error", fmt.Sprintf("Unable to update subdomain in yaml, got error: %s", err))
 		return
 	}
 
-	r.client.MarkZoneDirty(zone, fmt.Sprintf("chore(%s/%s): update %s record for %s", data.Scope.ValueString(), data.Zone.ValueString(), r.rtype.String(), data.Name.ValueString()))
+	r.client.MarkZoneDirty(ctx, zone, fmt.Sprintf("chore(%s/%s): update %s record for %s", data.Scope.ValueString(), data.Zone.ValueString(), rtype, data.Name.ValueString()), models.NewRecordUpsert(data.Name.ValueString(), record))
 
-	if err = r.client.FlushIfLast(); err != nil {
+	if err = r.client.FlushIfLast(ctx); err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not save zone: %s", err.Error()))
 		return
 	}
 
-	data.Id = types.StringValue(fmt.Sprintf("%s %s %s", data.Scope.ValueString(), data.Zone.ValueString(), data.Name.ValueString()))
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	data.Id = r.recordId(data, rtype)
+	resp.Diagnostics.Append(r.setState(ctx, &resp.State, data, rtype)...)
 }
 
 func (r *RecordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data *RecordModel
-
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	data, rtype, diags := r.getModel(ctx, req.State)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	r.client.InFlight.Add(1)
-	r.client.Mutex.Lock()
-	defer r.client.Mutex.Unlock()
+	r.client.Lock()
+	defer r.client.Unlock()
 
-	zone, err := r.client.GetZone(data.Zone.ValueString(), data.Scope.ValueString())
+	zone, err := r.client.GetZone(ctx, data.Zone.ValueString(), data.Scope.ValueString())
 	if err != nil {
-		_ = r.client.FlushIfLast()
+		_ = r.client.FlushIfLast(ctx)
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not retrieve zone: %s", err.Error()))
 		return
 	}
 
 	subdomain, err := zone.FindSubdomain(data.Name.ValueString())
 	if err != nil {
-		_ = r.client.FlushIfLast()
+		_ = r.client.FlushIfLast(ctx)
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find subdomain, got error: %s", err))
 		return
 	}
 
-	err = subdomain.DeleteType(r.rtype.String())
+	err = subdomain.DeleteType(rtype)
 	if err != nil {
-		_ = r.client.FlushIfLast()
+		_ = r.client.FlushIfLast(ctx)
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find type record, got error: %s", err))
 		return
 	}
 
 	err = subdomain.FindAllType()
 	if err != nil {
-		_ = r.client.FlushIfLast()
+		_ = r.client.FlushIfLast(ctx)
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not refresh all types of subdomain: %s", err.Error()))
 		return
 	}
@@ -469,15 +1031,15 @@ func (r *RecordResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	if len(subdomain.Types) == 0 {
 		err = zone.DeleteSubdomain(subdomain.Name)
 		if err != nil {
-			_ = r.client.FlushIfLast()
+			_ = r.client.FlushIfLast(ctx)
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete subdomain, got error: %s", err))
 			return
 		}
 	}
 
-	r.client.MarkZoneDirty(zone, fmt.Sprintf("chore(%s/%s): delete %s record for %s", data.Scope.ValueString(), data.Zone.ValueString(), r.rtype.String(), data.Name.ValueString()))
+	r.client.MarkZoneDirty(ctx, zone, fmt.Sprintf("chore(%s/%s): delete %s record for %s", data.Scope.ValueString(), data.Zone.ValueString(), rtype, data.Name.ValueString()), models.NewRecordDelete(data.Name.ValueString(), rtype))
 
-	if err = r.client.FlushIfLast(); err != nil {
+	if err = r.client.FlushIfLast(ctx); err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not save zone: %s", err.Error()))
 		return
 	}
@@ -0,0 +1,371 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/topicusonderwijs/terraform-provider-octodns/internal/models"
+)
+
+// validateConfig runs ValidateConfig of the resource on a config with the
+// given attributes.
+func validateConfig(t *testing.T, r *RecordResource, attributes map[string]tftypes.Value) diag.Diagnostics {
+	t.Helper()
+
+	schemaResp, typ := resourceSchema(t, r)
+	req := resource.ValidateConfigRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: objectValue(t, typ, attributes)},
+	}
+	resp := &resource.ValidateConfigResponse{}
+	r.ValidateConfig(context.Background(), req, resp)
+	return resp.Diagnostics
+}
+
+func TestEnabledTypes(t *testing.T) {
+	// a disabled type is left out
+	models.TYPES["DISABLED"] = models.RType{}
+	t.Cleanup(func() { delete(models.TYPES, "DISABLED") })
+
+	names := enabledTypes()
+	for i, name := range names {
+		if !models.TYPES[name].IsEnabled() {
+			t.Errorf("%s is not enabled", name)
+		}
+		if i > 0 && names[i-1] >= name {
+			t.Errorf("types are not sorted: %v", names)
+		}
+	}
+	for _, name := range []string{"A", "TXT", "URLFWD"} {
+		found := false
+		for _, enabled := range names {
+			found = found || enabled == name
+		}
+		if !found {
+			t.Errorf("expected %s to be enabled, got %v", name, names)
+		}
+	}
+}
+
+func TestRecordResource_TypeValidator(t *testing.T) {
+	models.TYPES["DISABLED"] = models.RType{}
+	t.Cleanup(func() { delete(models.TYPES, "DISABLED") })
+
+	schemaResp, _ := resourceSchema(t, &RecordResource{})
+	attribute, ok := schemaResp.Schema.Attributes["type"].(schema.StringAttribute)
+	if !ok {
+		t.Fatalf("expected a type attribute, got %T", schemaResp.Schema.Attributes["type"])
+	}
+
+	for rtype, valid := range map[string]bool{"A": true, "URLFWD": true, "DISABLED": false, "UNKNOWN": false, "a": false} {
+		req := validator.StringRequest{Path: path.Root("type"), ConfigValue: types.StringValue(rtype)}
+		resp := &validator.StringResponse{}
+		for _, v := range attribute.Validators {
+			v.ValidateString(context.Background(), req, resp)
+		}
+		if resp.Diagnostics.HasError() == valid {
+			t.Errorf("%s: expected valid %t, got %v", rtype, valid, resp.Diagnostics)
+		}
+	}
+}
+
+func TestRecordResource_ValidateConfigGeneric(t *testing.T) {
+	tests := []struct {
+		name   string
+		rtype  string
+		values []string
+		valid  bool
+	}{
+		{"valid", "A", []string{"1.1.1.1"}, true},
+		{"invalid", "A", []string{"example.com."}, false},
+		{"other type", "CNAME", []string{"example.com."}, true},
+		{"other type invalid", "CNAME", []string{"1.1.1.1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := validateConfig(t, &RecordResource{}, map[string]tftypes.Value{
+				"zone":   tftypes.NewValue(tftypes.String, "example.com"),
+				"name":   tftypes.NewValue(tftypes.String, "www"),
+				"type":   tftypes.NewValue(tftypes.String, tt.rtype),
+				"values": stringList(tt.values...),
+			})
+			if diags.HasError() == tt.valid {
+				t.Errorf("expected valid %t, got %v", tt.valid, diags)
+			}
+		})
+	}
+}
+
+func TestRecordResource_ValidateConfigUnknownType(t *testing.T) {
+	diags := validateConfig(t, &RecordResource{}, map[string]tftypes.Value{
+		"type":   tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"values": stringList("not validated"),
+	})
+	if diags.HasError() {
+		t.Errorf("values of an unknown type should not be validated, got %v", diags)
+	}
+}
+
+// TestRecordResource_ReadImportID reads a record from an imported ID, which
+// only sets the id attribute.
+func TestRecordResource_ReadImportID(t *testing.T) {
+	client, _ := newTestClient(t, "www:\n  type: A\n  value: 1.1.1.1\n")
+	r := &RecordResource{client: client}
+	schemaResp, typ := resourceSchema(t, r)
+
+	read := func(id string) *resource.ReadResponse {
+		state := tfsdk.State{Schema: schemaResp.Schema, Raw: objectValue(t, typ, map[string]tftypes.Value{
+			"id": tftypes.NewValue(tftypes.String, id),
+		})}
+		resp := &resource.ReadResponse{State: state}
+		r.Read(context.Background(), resource.ReadRequest{State: state}, resp)
+		return resp
+	}
+
+	resp := read("default example.com www a")
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read failed: %v", resp.Diagnostics)
+	}
+	for name, want := range map[string]string{"scope": "default", "zone": "example.com", "name": "www", "type": "A"} {
+		var got types.String
+		resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root(name), &got)...)
+		if got.ValueString() != want {
+			t.Errorf("expected %s %q, got %s", name, want, got)
+		}
+	}
+	var values []string
+	resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("values"), &values)...)
+	if len(values) != 1 || values[0] != "1.1.1.1" {
+		t.Errorf("expected values [1.1.1.1], got %v", values)
+	}
+	if resp.Diagnostics.HasError() {
+		t.Errorf("unexpected state: %v", resp.Diagnostics)
+	}
+
+	for _, id := range []string{"default example.com www", "default example.com www A extra"} {
+		resp := read(id)
+		if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "Malformed ID") {
+			t.Errorf("%q: expected a malformed ID error, got %v", id, resp.Diagnostics)
+		}
+	}
+}
+
+func TestRecordResource_ValidateConfigStructuredValues(t *testing.T) {
+	r := &RecordResource{rtype: &models.TYPE_MX}
+	_, typ := resourceSchema(t, r)
+	obj, ok := typ.(tftypes.Object)
+	if !ok {
+		t.Fatalf("expected an object type, got %s", typ)
+	}
+	listType, ok := obj.AttributeTypes["structured_values"].(tftypes.List)
+	if !ok {
+		t.Fatalf("expected structured_values to be a list, got %s", obj.AttributeTypes["structured_values"])
+	}
+
+	mx := func(preference interface{}, exchange string) tftypes.Value {
+		return objectValue(t, listType.ElementType, map[string]tftypes.Value{
+			"preference": tftypes.NewValue(tftypes.Number, preference),
+			"exchange":   tftypes.NewValue(tftypes.String, exchange),
+		})
+	}
+
+	tests := []struct {
+		name   string
+		values []tftypes.Value
+		errors []path.Path
+	}{
+		{"valid", []tftypes.Value{mx(10, "mx1.example.com."), mx(20, "mx2.example.com.")}, nil},
+		{"invalid", []tftypes.Value{mx(10, "mx1.example.com."), mx(20, "")}, []path.Path{path.Root("structured_values").AtListIndex(1)}},
+		{"unknown", []tftypes.Value{mx(tftypes.UnknownValue, "")}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := validateConfig(t, r, map[string]tftypes.Value{
+				"structured_values": tftypes.NewValue(listType, tt.values),
+			})
+			if got := diags.ErrorsCount(); got != len(tt.errors) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tt.errors), got, diags)
+			}
+			for i, d := range diags.Errors() {
+				withPath, ok := d.(diag.DiagnosticWithPath)
+				if !ok || !withPath.Path().Equal(tt.errors[i]) {
+					t.Errorf("expected an error at %s, got %v", tt.errors[i], d)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordResource_ValidateConfigStructuredValuesGeneric(t *testing.T) {
+	r := &RecordResource{}
+	_, typ := resourceSchema(t, r)
+	obj, ok := typ.(tftypes.Object)
+	if !ok {
+		t.Fatalf("expected an object type, got %s", typ)
+	}
+	listType, ok := obj.AttributeTypes["structured_values"].(tftypes.List)
+	if !ok {
+		t.Fatalf("expected structured_values to be a list, got %s", obj.AttributeTypes["structured_values"])
+	}
+
+	// the generic resource takes the fields of all types, the fields of
+	// another type are missing for MX
+	srv := objectValue(t, listType.ElementType, map[string]tftypes.Value{
+		"priority": tftypes.NewValue(tftypes.Number, 10),
+		"weight":   tftypes.NewValue(tftypes.Number, 20),
+		"port":     tftypes.NewValue(tftypes.Number, 443),
+		"target":   tftypes.NewValue(tftypes.String, "www.example.com."),
+	})
+
+	for rtype, valid := range map[string]bool{"SRV": true, "MX": false} {
+		diags := validateConfig(t, r, map[string]tftypes.Value{
+			"type":              tftypes.NewValue(tftypes.String, rtype),
+			"structured_values": tftypes.NewValue(listType, []tftypes.Value{srv}),
+		})
+		if diags.HasError() == valid {
+			t.Errorf("%s: expected valid %t, got %v", rtype, valid, diags)
+		}
+	}
+}
+
+func validateList(v valuesValidator, values ...string) diag.Diagnostics {
+	elems := []attr.Value{}
+	for _, value := range values {
+		elems = append(elems, types.StringValue(value))
+	}
+	req := validator.ListRequest{
+		Path:        path.Root("values"),
+		ConfigValue: types.ListValueMust(types.StringType, elems),
+	}
+	resp := &validator.ListResponse{}
+	v.ValidateList(context.Background(), req, resp)
+	return resp.Diagnostics
+}
+
+func TestValuesValidator(t *testing.T) {
+	long := strings.Repeat("a", models.TXT_CHUNK_SIZE+1)
+	spf := "v=spf1"
+	for _, host := range strings.Split("abcdefghijk", "") {
+		spf += " include:" + host + ".example.com"
+	}
+	spf += " -all"
+
+	tests := []struct {
+		name     string
+		v        valuesValidator
+		values   []string
+		errors   int
+		warnings int
+	}{
+		{"valid", valuesValidator{rtype: "A"}, []string{"1.1.1.1", "2.2.2.2"}, 0, 0},
+		{"invalid", valuesValidator{rtype: "A"}, []string{"1.1.1.1", "example.com"}, 1, 0},
+		{"long value split", valuesValidator{rtype: "TXT", splitLongValues: true}, []string{long}, 0, 0},
+		{"long value not split", valuesValidator{rtype: "TXT"}, []string{long}, 1, 0},
+		{"long value chunked", valuesValidator{rtype: "TXT"}, []string{models.ChunkValue(long)}, 0, 0},
+		{"unescaped semicolon", valuesValidator{rtype: "TXT"}, []string{"v=DKIM1; k=rsa"}, 1, 0},
+		{"escaped semicolon", valuesValidator{rtype: "TXT", escapeSemicolons: true}, []string{"v=DKIM1; k=rsa"}, 0, 0},
+		{"spf lookups", valuesValidator{rtype: "SPF"}, []string{spf}, 0, 1},
+		{"spf lookups in txt", valuesValidator{rtype: "TXT"}, []string{spf}, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := validateList(tt.v, tt.values...)
+			if got := diags.ErrorsCount(); got != tt.errors {
+				t.Errorf("expected %d errors, got %d: %v", tt.errors, got, diags)
+			}
+			if got := diags.WarningsCount(); got != tt.warnings {
+				t.Errorf("expected %d warnings, got %d: %v", tt.warnings, got, diags)
+			}
+		})
+	}
+}
+
+func TestValuesValidator_Unknown(t *testing.T) {
+	req := validator.ListRequest{
+		Path:        path.Root("values"),
+		ConfigValue: types.ListValueMust(types.StringType, []attr.Value{types.StringUnknown(), types.StringValue("1.1.1.1")}),
+	}
+	resp := &validator.ListResponse{}
+	valuesValidator{rtype: "A"}.ValidateList(context.Background(), req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("unknown values should be skipped, got %v", resp.Diagnostics)
+	}
+}
+
+func TestChunkedValue(t *testing.T) {
+	long := strings.Repeat("a", models.TXT_CHUNK_SIZE+1)
+
+	tests := []struct {
+		name   string
+		value  string
+		escape bool
+		split  bool
+		want   string
+	}{
+		{"short", "v=spf1 -all", true, true, "v=spf1 -all"},
+		{"escape", "v=DKIM1; k=rsa", true, false, `v=DKIM1\; k=rsa`},
+		{"no escape", "v=DKIM1; k=rsa", false, false, "v=DKIM1; k=rsa"},
+		{"split", long, false, true, `"` + long[:models.TXT_CHUNK_SIZE] + `" "a"`},
+		{"no split", long, false, false, long},
+		{"chunked", `"a" "b"`, false, true, `"a" "b"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chunkedValue(tt.value, tt.escape, tt.split); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRecordResource_ValidateConfigChunking(t *testing.T) {
+	long := strings.Repeat("a", models.TXT_CHUNK_SIZE+1)
+
+	tests := []struct {
+		name     string
+		values   []string
+		escape   interface{}
+		split    interface{}
+		errors   int
+		warnings int
+	}{
+		{"default split", []string{long}, nil, nil, 0, 0},
+		{"split disabled", []string{long}, false, false, 1, 0},
+		{"split unknown", []string{long}, false, tftypes.UnknownValue, 0, 0},
+		{"semicolon", []string{"v=DKIM1; k=rsa"}, false, true, 1, 0},
+		{"escaped semicolon", []string{"v=DKIM1; k=rsa"}, true, true, 0, 0},
+		{"spf lookups", []string{"v=spf1 a mx ptr exists:a.example.com exists:b.example.com include:c.example.com include:d.example.com include:e.example.com include:f.example.com include:g.example.com include:h.example.com -all"}, nil, nil, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := validateConfig(t, &RecordResource{rtype: &models.TYPE_TXT}, map[string]tftypes.Value{
+				"values":            stringList(tt.values...),
+				"escape_semicolons": tftypes.NewValue(tftypes.Bool, tt.escape),
+				"split_long_values": tftypes.NewValue(tftypes.Bool, tt.split),
+			})
+			if got := diags.ErrorsCount(); got != tt.errors {
+				t.Errorf("expected %d errors, got %d: %v", tt.errors, got, diags)
+			}
+			if got := diags.WarningsCount(); got != tt.warnings {
+				t.Errorf("expected %d warnings, got %d: %v", tt.warnings, got, diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/topicusonderwijs/terraform-provider-octodns/internal/models"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ZoneDataSource{}
+
+func NewZoneDataSource() datasource.DataSource {
+	return &ZoneDataSource{}
+}
+
+// ZoneDataSource lists all records of a zone.
+type ZoneDataSource struct {
+	client models.GitClient
+}
+
+func (d *ZoneDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone"
+}
+
+func (d *ZoneDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Zone data source, lists every subdomain of the zone with all its records",
+
+		Attributes: map[string]schema.Attribute{
+			"zone": schema.StringAttribute{
+				MarkdownDescription: "Name of the zone. eq: example.com",
+				Required:            true,
+			},
+			"scope": schema.StringAttribute{
+				MarkdownDescription: "Scope of zone",
+				Optional:            true,
+				Validators:          scopeValidators(),
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Zone identifier",
+				Computed:            true,
+			},
+			"subdomains": schema.ListNestedAttribute{
+				MarkdownDescription: "Subdomains of the zone sorted by name, the apex is named `@`",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Subdomain name",
+							Computed:            true,
+						},
+						"type": schema.ListNestedAttribute{
+							MarkdownDescription: "Records of the subdomain in the order of the zone file",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"type": schema.StringAttribute{
+										MarkdownDescription: "Record type",
+										Computed:            true,
+									},
+									"values": schema.ListAttribute{
+										MarkdownDescription: "Values of the record",
+										ElementType:         types.StringType,
+										Computed:            true,
+									},
+									"ttl": schema.Int64Attribute{
+										MarkdownDescription: "TTL of the record, if not set the zone's or dns server setting is used",
+										Computed:            true,
+									},
+									"octodns": schema.ObjectAttribute{
+										MarkdownDescription: "Additional provider specific record meta config, see the record data sources",
+										AttributeTypes:      OctodnsAttributeTypes(),
+										Computed:            true,
+									},
+									"ignored": schema.BoolAttribute{
+										MarkdownDescription: "The record is ignored by octoDNS",
+										Computed:            true,
+									},
+									"included": schema.ListAttribute{
+										MarkdownDescription: "The only providers the record is pushed to",
+										ElementType:         types.StringType,
+										Computed:            true,
+									},
+									"excluded": schema.ListAttribute{
+										MarkdownDescription: "Providers the record is not pushed to",
+										ElementType:         types.StringType,
+										Computed:            true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ZoneDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(models.GitClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected models.GitClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ZoneDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ZoneDataModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Reads don't take part in batching, but must not race the writes to
+	// the zone cache.
+	d.client.LockRead()
+	defer d.client.Unlock()
+
+	zone, err := d.client.GetZone(ctx, data.Zone.ValueString(), data.Scope.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not retrieve zone: %s", err.Error()))
+		return
+	}
+
+	recordTypes, err := zone.RecordTypes()
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not list records of zone: %s", err.Error()))
+		return
+	}
+
+	names := make([]string, 0, len(recordTypes))
+	for name := range recordTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	data.Subdomains = []SubdomainModel{}
+	for _, name := range names {
+		subdomain, err := zone.FindSubdomain(name)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read subdomain %s, got error: %s", name, err))
+			return
+		}
+
+		sub := SubdomainModel{Name: types.StringValue(subdomain.Name), Type: []TypeModel{}}
+		if subdomain.Name == "" {
+			sub.Name = types.StringValue("@")
+		}
+		for _, rtype := range recordTypes[name] {
+			record, err := subdomain.GetType(rtype)
+			if err != nil {
+				resp.Diagnostics.AddWarning("Unsupported Record", fmt.Sprintf("Skipped %s record of %s: %s", rtype, subdomain.Name, err))
+				continue
+			}
+
+			model := RecordModel{Octodns: types.ObjectNull(OctodnsAttributeTypes())}
+			resp.Diagnostics.Append(RecordToDataModel(ctx, &model, record)...)
+			sub.Type = append(sub.Type, TypeModel{
+				Type:     types.StringValue(rtype),
+				Values:   model.Values,
+				TTL:      model.TTL,
+				Octodns:  model.Octodns,
+				Ignored:  model.Ignored,
+				Included: model.Included,
+				Excluded: model.Excluded,
+			})
+		}
+		data.Subdomains = append(data.Subdomains, sub)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(fmt.Sprintf("%s %s", data.Scope.ValueString(), data.Zone.ValueString()))
+
+	tflog.Trace(ctx, "read a data source")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,360 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/topicusonderwijs/terraform-provider-octodns/internal/models"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ZoneResource{}
+var _ resource.ResourceWithImportState = &ZoneResource{}
+
+func NewZoneResource() resource.Resource {
+	return &ZoneResource{}
+}
+
+// ZoneResource creates and deletes zone files.
+type ZoneResource struct {
+	client models.GitClient
+}
+
+func (r *ZoneResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone"
+}
+
+func (r *ZoneResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Zone file resource, creates `<scope path>/<zone>.yaml` and removes it on destroy. " +
+			"The zone file is only removed when it holds no other records than the apex NS record managed by this resource, unless `force_destroy` is set. " +
+			"Adding the zone to the OctoDNS config is not done by this resource.",
+
+		Attributes: map[string]schema.Attribute{
+			"zone": schema.StringAttribute{
+				MarkdownDescription: "Name of the zone. eq: example.com",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"scope": schema.StringAttribute{
+				MarkdownDescription: "Scope of zone",
+				Optional:            true,
+				Validators:          scopeValidators(),
+				Computed:            true,
+				Default:             stringdefault.StaticString(models.DEFAULT_SCOPE),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Zone identifier",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"nameservers": schema.ListAttribute{
+				MarkdownDescription: "Nameservers of the apex NS record the zone is seeded with, leave empty to create an empty zone file",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.List{
+					valuesValidator{rtype: models.TYPE_NS.String()},
+				},
+			},
+			"ttl": schema.Int64Attribute{
+				MarkdownDescription: "TTL of the apex NS record",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(3600),
+			},
+			"force_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Remove the zone file on destroy even when it still holds records not managed by this resource",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+	}
+}
+
+func (r *ZoneResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	tflog.Trace(ctx, "- Resource Configure")
+
+	client, ok := req.ProviderData.(models.GitClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected models.GitClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// nameserverChange returns the change that sets the apex NS record to the
+// nameservers of data, or removes it when there are none.
+func nameserverChange(data *ZoneModel) (models.RecordChange, error) {
+	if len(data.Nameservers) == 0 {
+		return models.NewRecordDelete("", models.TYPE_NS.String()), nil
+	}
+
+	record := &models.Record{}
+	record.Type = models.TYPE_NS.String()
+	record.TTL = int(data.TTL.ValueInt64())
+	for _, ns := range data.Nameservers {
+		if err := record.AddValueFromString(ns.ValueString()); err != nil {
+			return models.RecordChange{}, err
+		}
+	}
+	return models.NewRecordUpsert("", record), nil
+}
+
+func (r *ZoneResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	tflog.Trace(ctx, "- Resource Create")
+	var data *ZoneModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Lock does the InFlight.Add(+1) BEFORE locking so all queued goroutines
+	// are counted. FlushIfLast owns the Add(-1) — do NOT defer it separately.
+	r.client.Lock()
+	defer r.client.Unlock()
+
+	zone, err := r.client.CreateZone(ctx, data.Zone.ValueString(), data.Scope.ValueString())
+	if err != nil {
+		_ = r.client.FlushIfLast(ctx)
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not create zone: %s", err.Error()))
+		return
+	}
+
+	changes := []models.Change{}
+	if len(data.Nameservers) > 0 {
+		change, err := nameserverChange(data)
+		if err == nil {
+			err = zone.ApplyChange(change)
+		}
+		if err != nil {
+			_ = r.client.FlushIfLast(ctx)
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create NS record, got error: %s", err))
+			return
+		}
+		changes = append(changes, change)
+	}
+
+	r.client.MarkZoneDirty(ctx, zone, fmt.Sprintf("chore(%s/%s): create zone", data.Scope.ValueString(), data.Zone.ValueString()), changes...)
+
+	// FlushIfLast does the InFlight.Add(-1) internally.
+	if err = r.client.FlushIfLast(ctx); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not save zone: %s", err.Error()))
+		return
+	}
+
+	data.Id = types.StringValue(fmt.Sprintf("%s %s", data.Scope.ValueString(), data.Zone.ValueString()))
+	tflog.Trace(ctx, "created a resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ZoneResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *ZoneModel
+	tflog.Trace(ctx, "- Resource Read")
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	parts := strings.Split(data.Id.ValueString(), " ")
+	if len(parts) != 2 {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Malformed ID: %s", data.Id.String()))
+		return
+	}
+
+	data.Scope = types.StringValue(parts[0])
+	data.Zone = types.StringValue(parts[1])
+	if data.TTL.IsNull() {
+		data.TTL = types.Int64Value(3600)
+	}
+	if data.ForceDestroy.IsNull() {
+		data.ForceDestroy = types.BoolValue(false)
+	}
+
+	// Reads don't take part in batching, but must not race the writes to
+	// the zone cache.
+	r.client.LockRead()
+	defer r.client.Unlock()
+
+	zone, err := r.client.GetZone(ctx, data.Zone.ValueString(), data.Scope.ValueString())
+	if errors.Is(err, models.ErrZoneNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Retreiving zone %s from scope %s resulted in error: %s", data.Zone.ValueString(), data.Scope.ValueString(), err.Error()))
+		return
+	}
+
+	// An apex NS record is only refreshed when this resource manages it, so
+	// one managed by an octodns_ns_record resource doesn't show up as drift.
+	if len(data.Nameservers) == 0 {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	data.Nameservers = nil
+	subdomain, err := zone.FindSubdomain("")
+	if err == nil {
+		var record *models.Record
+		record, err = subdomain.GetType(models.TYPE_NS.String())
+		if err == nil {
+			for _, value := range record.ValuesAsString() {
+				data.Nameservers = append(data.Nameservers, types.StringValue(value))
+			}
+			if record.TTL > 0 {
+				data.TTL = types.Int64Value(int64(record.TTL))
+			}
+		}
+	}
+	if err != nil && !errors.Is(err, models.ErrSubdomainNotFound) && !errors.Is(err, models.ErrTypeNotFound) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read NS record, got error: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ZoneResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *ZoneModel
+	tflog.Trace(ctx, "- Resource Update")
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.client.Lock()
+	defer r.client.Unlock()
+
+	zone, err := r.client.GetZone(ctx, data.Zone.ValueString(), data.Scope.ValueString())
+	if err != nil {
+		_ = r.client.FlushIfLast(ctx)
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not retrieve zone: %s", err.Error()))
+		return
+	}
+
+	change, err := nameserverChange(data)
+	if err == nil {
+		err = zone.ApplyChange(change)
+	}
+	if err != nil {
+		_ = r.client.FlushIfLast(ctx)
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update NS record, got error: %s", err))
+		return
+	}
+
+	r.client.MarkZoneDirty(ctx, zone, fmt.Sprintf("chore(%s/%s): update zone nameservers", data.Scope.ValueString(), data.Zone.ValueString()), change)
+
+	if err = r.client.FlushIfLast(ctx); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not save zone: %s", err.Error()))
+		return
+	}
+
+	data.Id = types.StringValue(fmt.Sprintf("%s %s", data.Scope.ValueString(), data.Zone.ValueString()))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ZoneResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *ZoneModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.client.Lock()
+	defer r.client.Unlock()
+
+	zone, err := r.client.GetZone(ctx, data.Zone.ValueString(), data.Scope.ValueString())
+	if errors.Is(err, models.ErrZoneNotFound) {
+		_ = r.client.FlushIfLast(ctx)
+		return
+	}
+	if err != nil {
+		_ = r.client.FlushIfLast(ctx)
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not retrieve zone: %s", err.Error()))
+		return
+	}
+
+	if !data.ForceDestroy.ValueBool() {
+		recordTypes, err := zone.RecordTypes()
+		if err != nil {
+			_ = r.client.FlushIfLast(ctx)
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not list records of zone: %s", err.Error()))
+			return
+		}
+
+		// only the apex NS record is owned by this resource
+		others := []string{}
+		for subdomain, rtypes := range recordTypes {
+			for _, rtype := range rtypes {
+				if subdomain == "" && rtype == models.TYPE_NS.String() && len(data.Nameservers) > 0 {
+					continue
+				}
+				name := subdomain
+				if name == "" {
+					name = "@"
+				}
+				others = append(others, fmt.Sprintf("%s %s", name, rtype))
+			}
+		}
+		if len(others) > 0 {
+			sort.Strings(others)
+			_ = r.client.FlushIfLast(ctx)
+			resp.Diagnostics.AddError(
+				"Zone Not Empty",
+				fmt.Sprintf("Zone %s still holds records not managed by this resource, remove them or set force_destroy: %s", data.Zone.ValueString(), strings.Join(others, ", ")),
+			)
+			return
+		}
+	}
+
+	zone.Delete()
+	r.client.MarkZoneDirty(ctx, zone, fmt.Sprintf("chore(%s/%s): delete zone", data.Scope.ValueString(), data.Zone.ValueString()))
+
+	if err = r.client.FlushIfLast(ctx); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Could not save zone: %s", err.Error()))
+		return
+	}
+
+	data.Id = types.StringNull()
+}
+
+func (r *ZoneResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
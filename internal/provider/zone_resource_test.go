@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/topicusonderwijs/terraform-provider-octodns/internal/models"
+)
+
+const (
+	apexZone    = "'':\n  type: NS\n  values:\n    - ns1.example.com.\n    - ns2.example.com.\n"
+	recordsZone = apexZone + "www:\n  type: A\n  value: 1.1.1.1\n"
+)
+
+// deleteZone runs Delete of the zone resource for a zone file with the given
+// contents, and returns the diagnostics and if the zone file is left.
+func deleteZone(t *testing.T, contents string, nameservers []string, forceDestroy bool) (*resource.DeleteResponse, bool) {
+	t.Helper()
+
+	client, filename := newTestClient(t, contents)
+
+	r := &ZoneResource{client: client}
+	schemaResp, typ := resourceSchema(t, r)
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: objectValue(t, typ, map[string]tftypes.Value{
+		"zone":          tftypes.NewValue(tftypes.String, "example.com"),
+		"scope":         tftypes.NewValue(tftypes.String, models.DEFAULT_SCOPE),
+		"id":            tftypes.NewValue(tftypes.String, "default example.com"),
+		"nameservers":   stringList(nameservers...),
+		"ttl":           tftypes.NewValue(tftypes.Number, 3600),
+		"force_destroy": tftypes.NewValue(tftypes.Bool, forceDestroy),
+	})}
+
+	resp := &resource.DeleteResponse{State: state}
+	r.Delete(context.Background(), resource.DeleteRequest{State: state}, resp)
+
+	_, err := os.Stat(filename)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		t.Fatal(err)
+	}
+	return resp, err == nil
+}
+
+func TestZoneResource_Delete(t *testing.T) {
+	nameservers := []string{"ns1.example.com.", "ns2.example.com."}
+
+	tests := []struct {
+		name         string
+		contents     string
+		nameservers  []string
+		forceDestroy bool
+		// others are the unmanaged records in the error, empty when the
+		// zone is deleted
+		others string
+	}{
+		{"managed nameservers", apexZone, nameservers, false, ""},
+		{"unmanaged nameservers", apexZone, nil, false, "@ NS"},
+		{"unmanaged records", recordsZone, nameservers, false, "www A"},
+		{"unmanaged records forced", recordsZone, nameservers, true, ""},
+		{"unmanaged nameservers forced", recordsZone, nil, true, ""},
+		{"empty zone", "{}\n", nil, false, ""},
+		{"zone gone", "", nameservers, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, left := deleteZone(t, tt.contents, tt.nameservers, tt.forceDestroy)
+
+			if tt.others == "" {
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("expected the zone to be deleted, got %v", resp.Diagnostics)
+				}
+				if left {
+					t.Errorf("expected the zone file to be removed")
+				}
+				return
+			}
+
+			if !resp.Diagnostics.HasError() {
+				t.Fatalf("expected the delete to be refused")
+			}
+			if summary := resp.Diagnostics[0].Summary(); summary != "Zone Not Empty" {
+				t.Errorf("expected Zone Not Empty, got %s", summary)
+			}
+			if detail := resp.Diagnostics[0].Detail(); !strings.HasSuffix(detail, "force_destroy: "+tt.others) {
+				t.Errorf("expected the unmanaged records %q, got %s", tt.others, detail)
+			}
+			if !left {
+				t.Errorf("expected the zone file to be left")
+			}
+		})
+	}
+}